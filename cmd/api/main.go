@@ -32,7 +32,22 @@ func main() {
 	
 	r.GET("/", handlers.RedirectToSwagger)
 	r.GET("/health", handlers.HealthHandler)
-	r.GET("/search", handlers.SearchHandler)
+	r.GET("/search", handlers.NegotiateResponseFormat, handlers.SearchHandler)
+	r.GET("/lookup/isrc/:isrc", handlers.NegotiateResponseFormat, handlers.LookupISRCHandler)
+	r.GET("/lookup/musicbrainz/:mbid", handlers.NegotiateResponseFormat, handlers.LookupMusicBrainzHandler)
+	r.GET("/lookup/spotify", handlers.NegotiateResponseFormat, handlers.LookupSpotifyHandler)
+	r.GET("/videos/:id", handlers.NegotiateResponseFormat, handlers.VideoHandler)
+	r.GET("/thumbnails/:id", handlers.ThumbnailHandler)
+	r.GET("/oembed", handlers.OEmbedHandler)
+	r.GET("/watch", handlers.WatchHandler)
+	r.POST("/albums/resolve", handlers.NegotiateResponseFormat, handlers.ResolveAlbumHandler)
+	r.POST("/import/playlist", handlers.NegotiateResponseFormat, handlers.ImportPlaylistHandler)
+	r.POST("/import/csv", handlers.ImportCSVHandler)
+	r.POST("/import/itunes", handlers.NegotiateResponseFormat, handlers.ImportITunesHandler)
+	r.POST("/import/scrobbles", handlers.NegotiateResponseFormat, handlers.ImportScrobblesHandler)
+	r.POST("/export/watch-videos", handlers.NegotiateResponseFormat, handlers.ExportWatchVideosHandler)
+	r.POST("/export/m3u", handlers.ExportM3UHandler)
+	r.POST("/export/json", handlers.NegotiateResponseFormat, handlers.ExportJSONHandler)
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	
 	r.Run(":" + port)
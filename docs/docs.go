@@ -15,48 +15,145 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/health": {
-            "get": {
-                "description": "Returns the health status of the API",
-                "produces": [
+        "/albums/resolve": {
+            "post": {
+                "description": "Resolves an ordered tracklist with the album title added to each search. A video is only used for one track; when a track has no other match, it keeps the video and duplicateOf names the track that already uses it. A track whose search fails has no video and a reason.",
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
                 "tags": [
-                    "health"
+                    "albums"
+                ],
+                "summary": "Find music videos for every track on an album",
+                "parameters": [
+                    {
+                        "description": "Album artist, title and ordered tracklist",
+                        "name": "album",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AlbumRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 region code, e.g. SE",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language code, e.g. sv or en-US",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates that aren't playable in region",
+                        "name": "playable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates with embedding disabled",
+                        "name": "embeddable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip age-restricted candidates",
+                        "name": "exclude_age_restricted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Allowed duration difference in seconds",
+                        "name": "duration_tolerance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates outside the duration tolerance instead of ranking them lower",
+                        "name": "strict_duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Return no video unless a candidate's confidence, between 0 and 1, reaches this",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format when the Accept header can't be set: json, xml, yaml or msgpack",
+                        "name": "format",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Health check endpoint",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.HealthResponse"
+                            "$ref": "#/definitions/handlers.AlbumResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
         },
-        "/search": {
-            "get": {
-                "description": "Returns a list of music video search results",
-                "produces": [
+        "/export/json": {
+            "post": {
+                "description": "Returns the YouTube Data API resources that create the playlist: send playlist to playlists.insert, then each of items to playlistItems.insert with snippet.playlistId set to the new playlist's ID",
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
                 "tags": [
-                    "search"
+                    "export"
                 ],
-                "summary": "Search for music videos",
+                "summary": "Export videos for scripted playlist creation",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Title to search for",
-                        "name": "title",
-                        "in": "query",
-                        "required": true
+                        "description": "Ordered videos, e.g. the video of each entry of an import",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExportRequest"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "Artist name or comma-separated list of artists",
-                        "name": "artists",
+                        "description": "Response format when the Accept header can't be set: json, xml, yaml or msgpack",
+                        "name": "format",
                         "in": "query"
                     }
                 ],
@@ -64,7 +161,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.SearchResponse"
+                            "$ref": "#/definitions/exporters.PlaylistDocument"
                         }
                     },
                     "400": {
@@ -75,53 +172,2195 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.HealthResponse": {
-            "type": "object",
-            "properties": {
-                "status": {
-                    "type": "string"
+        },
+        "/export/m3u": {
+            "post": {
+                "description": "Returns an extended M3U playlist of watch URLs for players that stream YouTube, such as VLC or mpv",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "audio/x-mpegurl"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export videos as an M3U playlist",
+                "parameters": [
+                    {
+                        "description": "Ordered videos, e.g. the video of each entry of an import",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
                 }
             }
         },
-        "handlers.SearchInput": {
-            "type": "object",
-            "properties": {
-                "artists": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        "/export/watch-videos": {
+            "post": {
+                "description": "Returns watch_videos URLs that play the videos in order without signing in. YouTube plays at most 50 videos per URL, so longer lists are split over several URLs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Export videos as anonymous YouTube playlists",
+                "parameters": [
+                    {
+                        "description": "Ordered videos, e.g. the video of each entry of an import",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ExportRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format when the Accept header can't be set: json, xml, yaml or msgpack",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WatchVideosResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
-                },
-                "title": {
-                    "type": "string"
                 }
             }
         },
-        "handlers.SearchResponse": {
-            "type": "object",
-            "properties": {
-                "input": {
-                    "$ref": "#/definitions/handlers.SearchInput"
-                },
-                "video": {
-                    "$ref": "#/definitions/handlers.SearchVideo"
+        "/health": {
+            "get": {
+                "description": "Returns the health status of the API",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check endpoint",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HealthResponse"
+                        }
+                    }
                 }
             }
         },
-        "handlers.SearchVideo": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "type": "string"
-                },
-                "url": {
-                    "type": "string"
+        "/import/csv": {
+            "post": {
+                "description": "Resolves each row of a CSV with a header row and streams the same CSV back with video_id, video_url, confidence and status columns appended. Rows without a title are looked up by their ISRC, MusicBrainz ID or Spotify URI. The status is matched, low_confidence, no_match, not_found, skipped or error. When the input can't be read to the end, a last row with status error explains why in its first column.",
+                "consumes": [
+                    "text/csv",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Find music videos for every row of a CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file, when uploading as multipart/form-data instead of sending it as the body",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Header of the title column, default title, track, song or name",
+                        "name": "title_column",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Header of the artists column, default artists or artist",
+                        "name": "artists_column",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Header of the duration column (seconds or mm:ss), default duration or length",
+                        "name": "duration_column",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Header of the external ID column, default id, external_id or isrc",
+                        "name": "id_column",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field delimiter: comma, semicolon or tab",
+                        "name": "delimiter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 region code, e.g. SE",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language code, e.g. sv or en-US",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates that aren't playable in region",
+                        "name": "playable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates with embedding disabled",
+                        "name": "embeddable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip age-restricted candidates",
+                        "name": "exclude_age_restricted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Allowed duration difference in seconds",
+                        "name": "duration_tolerance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates outside the duration tolerance instead of ranking them lower",
+                        "name": "strict_duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Return no video unless a candidate's confidence, between 0 and 1, reaches this",
+                        "name": "min_confidence",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The CSV with result columns appended",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/import/itunes": {
+            "post": {
+                "description": "Parses an uploaded iTunes or Apple Music Library.xml and resolves its tracks to music videos, grouped by playlist. Each track is searched for once, however many playlists it is in.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Find music videos for an iTunes library",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "iTunes Library.xml",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only import these playlists; by default every playlist and the tracks in none of them are imported",
+                        "name": "playlist",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 region code, e.g. SE",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language code, e.g. sv or en-US",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates that aren't playable in region",
+                        "name": "playable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates with embedding disabled",
+                        "name": "embeddable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip age-restricted candidates",
+                        "name": "exclude_age_restricted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Allowed duration difference in seconds",
+                        "name": "duration_tolerance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates outside the duration tolerance instead of ranking them lower",
+                        "name": "strict_duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Return no video unless a candidate's confidence, between 0 and 1, reaches this",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format when the Accept header can't be set: json, xml, yaml or msgpack",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ITunesImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/import/playlist": {
+            "post": {
+                "description": "Parses an uploaded M3U/M3U8, XSPF or PLS playlist and resolves every entry to a music video",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Find music videos for a playlist file",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "M3U, M3U8, XSPF or PLS playlist",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 region code, e.g. SE",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language code, e.g. sv or en-US",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates that aren't playable in region",
+                        "name": "playable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates with embedding disabled",
+                        "name": "embeddable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip age-restricted candidates",
+                        "name": "exclude_age_restricted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Allowed duration difference in seconds",
+                        "name": "duration_tolerance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates outside the duration tolerance instead of ranking them lower",
+                        "name": "strict_duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Return no video unless a candidate's confidence, between 0 and 1, reaches this",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format when the Accept header can't be set: json, xml, yaml or msgpack",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/import/scrobbles": {
+            "post": {
+                "description": "Counts the listens in an uploaded Last.fm scrobble CSV or ListenBrainz JSON export by song, and resolves the most played songs to music videos",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "import"
+                ],
+                "summary": "Find music videos for the most played songs of a listening history",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Last.fm CSV or ListenBrainz JSON/JSONL export",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of songs to resolve, 1 to 100, default 25",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 region code, e.g. SE",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language code, e.g. sv or en-US",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates that aren't playable in region",
+                        "name": "playable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates with embedding disabled",
+                        "name": "embeddable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip age-restricted candidates",
+                        "name": "exclude_age_restricted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Return no video unless a candidate's confidence, between 0 and 1, reaches this",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format when the Accept header can't be set: json, xml, yaml or msgpack",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ScrobbleImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/lookup/isrc/{isrc}": {
+            "get": {
+                "description": "Resolves an ISRC to a title and artists through MusicBrainz, then searches YouTube for it",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "lookup"
+                ],
+                "summary": "Find the music video for an ISRC",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ISRC, with or without hyphens, e.g. USRC17607839",
+                        "name": "isrc",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 region code, e.g. SE",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language code, e.g. sv or en-US",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Report whether the video is playable in region",
+                        "name": "availability",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates that aren't playable in region",
+                        "name": "playable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates with embedding disabled",
+                        "name": "embeddable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip age-restricted candidates",
+                        "name": "exclude_age_restricted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected track length in seconds or mm:ss, overriding the recording's length",
+                        "name": "duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Allowed duration difference in seconds",
+                        "name": "duration_tolerance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates outside the duration tolerance instead of ranking them lower",
+                        "name": "strict_duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Return no video unless a candidate's confidence, between 0 and 1, reaches this",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "youtube (default) searches youtube.com; music searches YouTube Music and also returns the song's audio track",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "List every ranked candidate with its similarity score",
+                        "name": "include_candidates",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format when the Accept header can't be set: json, xml, yaml or msgpack",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LookupResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/lookup/musicbrainz/{mbid}": {
+            "get": {
+                "description": "Fetches a MusicBrainz recording's title, artists and length, then searches YouTube for it",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "lookup"
+                ],
+                "summary": "Find the music video for a MusicBrainz recording",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "MusicBrainz recording ID",
+                        "name": "mbid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 region code, e.g. SE",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language code, e.g. sv or en-US",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Report whether the video is playable in region",
+                        "name": "availability",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates that aren't playable in region",
+                        "name": "playable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates with embedding disabled",
+                        "name": "embeddable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip age-restricted candidates",
+                        "name": "exclude_age_restricted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected track length in seconds or mm:ss, overriding the recording's length",
+                        "name": "duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Allowed duration difference in seconds",
+                        "name": "duration_tolerance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates outside the duration tolerance instead of ranking them lower",
+                        "name": "strict_duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Return no video unless a candidate's confidence, between 0 and 1, reaches this",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "youtube (default) searches youtube.com; music searches YouTube Music and also returns the song's audio track",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "List every ranked candidate with its similarity score",
+                        "name": "include_candidates",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format when the Accept header can't be set: json, xml, yaml or msgpack",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LookupResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/lookup/spotify": {
+            "get": {
+                "description": "Resolves a Spotify track's title, artists and duration, then searches YouTube for it",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "lookup"
+                ],
+                "summary": "Find the music video for a Spotify track",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "spotify:track: URI, open.spotify.com track URL or track ID",
+                        "name": "uri",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 region code, e.g. SE",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language code, e.g. sv or en-US",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Report whether the video is playable in region",
+                        "name": "availability",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates that aren't playable in region",
+                        "name": "playable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates with embedding disabled",
+                        "name": "embeddable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip age-restricted candidates",
+                        "name": "exclude_age_restricted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected track length in seconds or mm:ss, overriding the recording's length",
+                        "name": "duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Allowed duration difference in seconds",
+                        "name": "duration_tolerance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates outside the duration tolerance instead of ranking them lower",
+                        "name": "strict_duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Return no video unless a candidate's confidence, between 0 and 1, reaches this",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "youtube (default) searches youtube.com; music searches YouTube Music and also returns the song's audio track",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "List every ranked candidate with its similarity score",
+                        "name": "include_candidates",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format when the Accept header can't be set: json, xml, yaml or msgpack",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LookupResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/oembed": {
+            "get": {
+                "description": "Resolves a song to an embeddable music video and returns an oEmbed 1.0 video response with the YouTube iframe. The song is given as title and artists, or as url holding the text an editor pasted, such as \"Loreen - Euphoria\".",
+                "produces": [
+                    "application/json",
+                    "text/xml"
+                ],
+                "tags": [
+                    "oembed"
+                ],
+                "summary": "Get an oEmbed embed for a song",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Song as \\",
+                        "name": "url",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Title to search for",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Artist name or comma-separated list of artists",
+                        "name": "artists",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum embed and thumbnail width in pixels",
+                        "name": "maxwidth",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum embed and thumbnail height in pixels",
+                        "name": "maxheight",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or xml",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 region code, e.g. SE",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language code, e.g. sv or en-US",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected track length in seconds or mm:ss",
+                        "name": "duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Return no video unless a candidate's confidence, between 0 and 1, reaches this",
+                        "name": "min_confidence",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OEmbedResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "501": {
+                        "description": "Not Implemented",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Returns a list of music video search results",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search for music videos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Title to search for",
+                        "name": "title",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Artist name or comma-separated list of artists",
+                        "name": "artists",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 region code, e.g. SE",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language code, e.g. sv or en-US",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Report whether the video is playable in region",
+                        "name": "availability",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates that aren't playable in region",
+                        "name": "playable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates with embedding disabled",
+                        "name": "embeddable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip age-restricted candidates",
+                        "name": "exclude_age_restricted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected track length in seconds or mm:ss",
+                        "name": "duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Allowed duration difference in seconds",
+                        "name": "duration_tolerance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates outside the duration tolerance instead of ranking them lower",
+                        "name": "strict_duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Return no video unless a candidate's confidence, between 0 and 1, reaches this",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "youtube (default) searches youtube.com; music searches YouTube Music and also returns the song's audio track",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "List every ranked candidate with its similarity score",
+                        "name": "include_candidates",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format when the Accept header can't be set: json, xml, yaml or msgpack",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/thumbnails/{id}": {
+            "get": {
+                "description": "Proxies a thumbnail from i.ytimg.com. When the video has no thumbnail of the requested quality, the next smaller one is returned. Thumbnails can be resized to fit within width and height. WebP thumbnails are YouTube's own WebP variant when it has one and no resizing is asked for, and otherwise the JPEG converted to lossless WebP. Images are cached on disk.",
+                "produces": [
+                    "image/jpeg",
+                    "image/webp"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Get a video's thumbnail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "YouTube video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "default (120x90), mq (320x180), hq (480x360, default), sd (640x480) or maxres (1280x720)",
+                        "name": "quality",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "jpeg (default) or webp",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum width in pixels, at most 1280",
+                        "name": "width",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum height in pixels, at most 1280",
+                        "name": "height",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/videos/{id}": {
+            "get": {
+                "description": "Returns a video's title, channel, duration, publish date, view count, thumbnails and, when YouTube lists it, the music used in the video",
+                "produces": [
+                    "application/json",
+                    "text/xml",
+                    "application/yaml",
+                    "application/msgpack"
+                ],
+                "tags": [
+                    "videos"
+                ],
+                "summary": "Get the metadata of a video",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "YouTube video ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format when the Accept header can't be set: json, xml, yaml or msgpack",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.VideoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "406": {
+                        "description": "Not Acceptable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/watch": {
+            "get": {
+                "description": "Resolves a song and redirects to its video on YouTube, YouTube Music or the embeddable player. When no video is found, the client is redirected to the configured fallback page, or else to the search results for the song.",
+                "tags": [
+                    "watch"
+                ],
+                "summary": "Redirect to the music video for a song",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Title to search for",
+                        "name": "title",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Artist name or comma-separated list of artists",
+                        "name": "artists",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "watch (default), music or embed",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ISO 3166-1 alpha-2 region code, e.g. SE",
+                        "name": "region",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Language code, e.g. sv or en-US",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip candidates that aren't playable in region",
+                        "name": "playable",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected track length in seconds or mm:ss",
+                        "name": "duration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Redirect to the fallback page unless a candidate's confidence, between 0 and 1, reaches this",
+                        "name": "min_confidence",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "exporters.PlaylistDocument": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/exporters.PlaylistItem"
+                    }
+                },
+                "playlist": {
+                    "$ref": "#/definitions/exporters.PlaylistResource"
+                }
+            }
+        },
+        "exporters.PlaylistItem": {
+            "type": "object",
+            "properties": {
+                "snippet": {
+                    "$ref": "#/definitions/exporters.PlaylistItemSnippet"
+                },
+                "title": {
+                    "description": "Title and URL aren't part of the API resource; they make the\ndocument readable and are ignored by the API.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "exporters.PlaylistItemSnippet": {
+            "type": "object",
+            "properties": {
+                "playlistId": {
+                    "type": "string"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "resourceId": {
+                    "$ref": "#/definitions/exporters.ResourceID"
+                }
+            }
+        },
+        "exporters.PlaylistResource": {
+            "type": "object",
+            "properties": {
+                "snippet": {
+                    "$ref": "#/definitions/exporters.PlaylistSnippet"
+                },
+                "status": {
+                    "$ref": "#/definitions/exporters.PlaylistStatus"
+                }
+            }
+        },
+        "exporters.PlaylistSnippet": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "exporters.PlaylistStatus": {
+            "type": "object",
+            "properties": {
+                "privacyStatus": {
+                    "type": "string"
+                }
+            }
+        },
+        "exporters.ResourceID": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "type": "string"
+                },
+                "videoId": {
+                    "type": "string"
+                }
+            }
+        },
+        "exporters.Video": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "seconds, 0 when unknown",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AlbumRequest": {
+            "type": "object",
+            "properties": {
+                "album": {
+                    "type": "string"
+                },
+                "artist": {
+                    "type": "string"
+                },
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AlbumTrack"
+                    }
+                }
+            }
+        },
+        "handlers.AlbumResponse": {
+            "type": "object",
+            "properties": {
+                "album": {
+                    "type": "string"
+                },
+                "artist": {
+                    "type": "string"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "resolved": {
+                    "type": "integer"
+                },
+                "tracks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AlbumTrackResult"
+                    }
+                }
+            }
+        },
+        "handlers.AlbumTrack": {
+            "type": "object",
+            "properties": {
+                "artists": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "duration": {
+                    "description": "seconds",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AlbumTrackResult": {
+            "type": "object",
+            "properties": {
+                "artists": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "duplicateOf": {
+                    "type": "integer"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.SkippedCandidate"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "video": {
+                    "$ref": "#/definitions/handlers.SearchVideo"
+                }
+            }
+        },
+        "handlers.ExportRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "privacy": {
+                    "description": "Privacy is private (default), unlisted or public.",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "videos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/exporters.Video"
+                    }
+                }
+            }
+        },
+        "handlers.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "proxies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.ProxyStats"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ITunesImportResponse": {
+            "type": "object",
+            "properties": {
+                "playlists": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ITunesPlaylistResult"
+                    }
+                },
+                "resolved": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unlisted": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TrackResult"
+                    }
+                }
+            }
+        },
+        "handlers.ITunesPlaylistResult": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TrackResult"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ImportResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TrackResult"
+                    }
+                },
+                "format": {
+                    "type": "string"
+                },
+                "resolved": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.LookupResponse": {
+            "type": "object",
+            "properties": {
+                "audio": {
+                    "$ref": "#/definitions/handlers.SearchVideo"
+                },
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SearchVideo"
+                    }
+                },
+                "input": {
+                    "$ref": "#/definitions/handlers.SearchInput"
+                },
+                "isrc": {
+                    "type": "string"
+                },
+                "lowConfidenceCandidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SearchVideo"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "recording": {
+                    "$ref": "#/definitions/services.Recording"
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.SkippedCandidate"
+                    }
+                },
+                "spotifyId": {
+                    "type": "string"
+                },
+                "video": {
+                    "$ref": "#/definitions/handlers.SearchVideo"
+                }
+            }
+        },
+        "handlers.OEmbedResponse": {
+            "type": "object",
+            "properties": {
+                "author_name": {
+                    "type": "string"
+                },
+                "author_url": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "html": {
+                    "type": "string"
+                },
+                "provider_name": {
+                    "type": "string"
+                },
+                "provider_url": {
+                    "type": "string"
+                },
+                "thumbnail_height": {
+                    "type": "integer"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "thumbnail_width": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.RankedTrackResult": {
+            "type": "object",
+            "properties": {
+                "album": {
+                    "type": "string"
+                },
+                "artists": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "plays": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "video": {
+                    "$ref": "#/definitions/handlers.SearchVideo"
+                }
+            }
+        },
+        "handlers.ScrobbleImportResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.RankedTrackResult"
+                    }
+                },
+                "listens": {
+                    "type": "integer"
+                },
+                "resolved": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "tracks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.SearchInput": {
+            "type": "object",
+            "properties": {
+                "artists": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "credits": {
+                    "$ref": "#/definitions/services.Credits"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "lang": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "audio": {
+                    "$ref": "#/definitions/handlers.SearchVideo"
+                },
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SearchVideo"
+                    }
+                },
+                "input": {
+                    "$ref": "#/definitions/handlers.SearchInput"
+                },
+                "lowConfidenceCandidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SearchVideo"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.SkippedCandidate"
+                    }
+                },
+                "video": {
+                    "$ref": "#/definitions/handlers.SearchVideo"
+                }
+            }
+        },
+        "handlers.SearchVideo": {
+            "type": "object",
+            "properties": {
+                "ageRestricted": {
+                    "type": "boolean"
+                },
+                "availability": {
+                    "$ref": "#/definitions/services.Availability"
+                },
+                "channel": {
+                    "type": "string"
+                },
+                "confidence": {
+                    "type": "number"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "embeddable": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "similarity": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.TrackResult": {
+            "type": "object",
+            "properties": {
+                "album": {
+                    "type": "string"
+                },
+                "artists": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "video": {
+                    "$ref": "#/definitions/handlers.SearchVideo"
+                }
+            }
+        },
+        "handlers.VideoResponse": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "channelId": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "seconds",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "music": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.MusicAttribution"
+                    }
+                },
+                "publishDate": {
+                    "type": "string"
+                },
+                "thumbnails": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.Thumbnail"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "viewCount": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.WatchVideosResponse": {
+            "type": "object",
+            "properties": {
+                "urls": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "videos": {
+                    "type": "integer"
+                }
+            }
+        },
+        "services.Availability": {
+            "type": "object",
+            "properties": {
+                "allowedRegions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "playable": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.Credits": {
+            "type": "object",
+            "properties": {
+                "featured": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "primary": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.MusicAttribution": {
+            "type": "object",
+            "properties": {
+                "album": {
+                    "type": "string"
+                },
+                "artist": {
+                    "type": "string"
+                },
+                "licensedTo": {
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.ProxyStats": {
+            "type": "object",
+            "properties": {
+                "consecutiveFailures": {
+                    "type": "integer"
+                },
+                "recentFailures": {
+                    "type": "number"
+                },
+                "retired": {
+                    "type": "boolean"
+                },
+                "retryAt": {
+                    "type": "string"
+                },
+                "totalFailures": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.Recording": {
+            "type": "object",
+            "properties": {
+                "artists": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "duration": {
+                    "description": "seconds, 0 when unknown",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.SkippedCandidate": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.Thumbnail": {
+            "type": "object",
+            "properties": {
+                "height": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
                 }
             }
         }
@@ -0,0 +1,69 @@
+package exporters
+
+// PlaylistDocument describes a playlist in the shape of the YouTube Data
+// API: Playlist is the body of a playlists.insert call and each of Items the
+// body of a playlistItems.insert call once snippet.playlistId is filled in
+// with the new playlist's ID.
+type PlaylistDocument struct {
+	Playlist PlaylistResource `json:"playlist"`
+	Items    []PlaylistItem   `json:"items"`
+}
+
+type PlaylistResource struct {
+	Snippet PlaylistSnippet `json:"snippet"`
+	Status  PlaylistStatus  `json:"status"`
+}
+
+type PlaylistSnippet struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+}
+
+type PlaylistStatus struct {
+	PrivacyStatus string `json:"privacyStatus"`
+}
+
+type PlaylistItem struct {
+	Snippet PlaylistItemSnippet `json:"snippet"`
+
+	// Title and URL aren't part of the API resource; they make the
+	// document readable and are ignored by the API.
+	Title string `json:"title,omitempty"`
+	URL   string `json:"url"`
+}
+
+type PlaylistItemSnippet struct {
+	PlaylistID string     `json:"playlistId"`
+	Position   int        `json:"position"`
+	ResourceID ResourceID `json:"resourceId"`
+}
+
+type ResourceID struct {
+	Kind    string `json:"kind"`
+	VideoID string `json:"videoId"`
+}
+
+// NewPlaylistDocument builds the document for videos. privacy is private,
+// unlisted or public.
+func NewPlaylistDocument(title, description, privacy string, videos []Video) PlaylistDocument {
+	document := PlaylistDocument{
+		Playlist: PlaylistResource{
+			Snippet: PlaylistSnippet{Title: title, Description: description},
+			Status:  PlaylistStatus{PrivacyStatus: privacy},
+		},
+		Items: make([]PlaylistItem, 0, len(videos)),
+	}
+
+	for i, video := range videos {
+		document.Items = append(document.Items, PlaylistItem{
+			Snippet: PlaylistItemSnippet{
+				Position:   i,
+				ResourceID: ResourceID{Kind: "youtube#video", VideoID: video.ID},
+			},
+			Title: video.Title,
+			URL:   WatchURL(video.ID),
+		})
+	}
+
+	return document
+}
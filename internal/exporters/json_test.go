@@ -0,0 +1,33 @@
+package exporters
+
+import "testing"
+
+func TestNewPlaylistDocument(t *testing.T) {
+	document := NewPlaylistDocument("Road trip", "Songs for the drive", "unlisted", []Video{
+		{ID: "dQw4w9WgXcQ", Title: "Never Gonna Give You Up"},
+		{ID: "Pfhpe6shO2U"},
+	})
+
+	if document.Playlist.Snippet.Title != "Road trip" || document.Playlist.Snippet.Description != "Songs for the drive" {
+		t.Errorf("Expected the playlist title and description, got %+v", document.Playlist.Snippet)
+	}
+	if document.Playlist.Status.PrivacyStatus != "unlisted" {
+		t.Errorf("Expected privacy status unlisted, got %q", document.Playlist.Status.PrivacyStatus)
+	}
+
+	if len(document.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(document.Items))
+	}
+	for i, id := range []string{"dQw4w9WgXcQ", "Pfhpe6shO2U"} {
+		item := document.Items[i]
+		if item.Snippet.Position != i {
+			t.Errorf("Expected item %d at position %d, got %d", i, i, item.Snippet.Position)
+		}
+		if item.Snippet.ResourceID != (ResourceID{Kind: "youtube#video", VideoID: id}) {
+			t.Errorf("Expected item %d to reference %s, got %+v", i, id, item.Snippet.ResourceID)
+		}
+		if item.URL != WatchURL(id) {
+			t.Errorf("Expected item %d URL %s, got %s", i, WatchURL(id), item.URL)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package exporters
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteM3U writes videos as an extended M3U playlist of watch URLs, which
+// players such as VLC and mpv can stream.
+func WriteM3U(w io.Writer, title string, videos []Video) error {
+	b := bufio.NewWriter(w)
+
+	b.WriteString("#EXTM3U\n")
+	if title = m3uText(title); title != "" {
+		fmt.Fprintf(b, "#PLAYLIST:%s\n", title)
+	}
+
+	for _, video := range videos {
+		duration := video.Duration
+		if duration <= 0 {
+			duration = -1
+		}
+		name := m3uText(video.Title)
+		if name == "" {
+			name = video.ID
+		}
+		fmt.Fprintf(b, "#EXTINF:%d,%s\n%s\n", duration, name, WatchURL(video.ID))
+	}
+
+	return b.Flush()
+}
+
+// m3uText keeps s on a single line.
+func m3uText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,27 @@
+package exporters
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteM3U(t *testing.T) {
+	var b strings.Builder
+	err := WriteM3U(&b, "Road\ntrip", []Video{
+		{ID: "dQw4w9WgXcQ", Title: "Rick Astley - Never Gonna Give You Up", Duration: 213},
+		{ID: "Pfhpe6shO2U"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "#EXTM3U\n" +
+		"#PLAYLIST:Road trip\n" +
+		"#EXTINF:213,Rick Astley - Never Gonna Give You Up\n" +
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ\n" +
+		"#EXTINF:-1,Pfhpe6shO2U\n" +
+		"https://www.youtube.com/watch?v=Pfhpe6shO2U\n"
+	if b.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, b.String())
+	}
+}
@@ -0,0 +1,17 @@
+// Package exporters writes lists of resolved videos in formats that can be
+// opened on YouTube or turned into a YouTube playlist.
+package exporters
+
+import "net/url"
+
+// Video is an entry of an exported playlist.
+type Video struct {
+	ID       string `json:"id"`
+	Title    string `json:"title,omitempty"`
+	Duration int    `json:"duration,omitempty"` // seconds, 0 when unknown
+}
+
+// WatchURL returns the youtube.com URL of the video with the given ID.
+func WatchURL(id string) string {
+	return "https://www.youtube.com/watch?" + url.Values{"v": {id}}.Encode()
+}
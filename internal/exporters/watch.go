@@ -0,0 +1,19 @@
+package exporters
+
+import "strings"
+
+// MaxWatchVideos is the most videos YouTube plays from a single
+// watch_videos URL; later IDs are silently dropped.
+const MaxWatchVideos = 50
+
+// WatchVideosURLs returns anonymous playlist URLs of the form
+// https://www.youtube.com/watch_videos?video_ids=a,b,c that together play
+// ids in order, each URL holding at most MaxWatchVideos videos.
+func WatchVideosURLs(ids []string) []string {
+	var urls []string
+	for start := 0; start < len(ids); start += MaxWatchVideos {
+		chunk := ids[start:min(start+MaxWatchVideos, len(ids))]
+		urls = append(urls, "https://www.youtube.com/watch_videos?video_ids="+strings.Join(chunk, ","))
+	}
+	return urls
+}
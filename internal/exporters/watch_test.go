@@ -0,0 +1,42 @@
+package exporters
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWatchVideosURLs(t *testing.T) {
+	var ids []string
+	for i := 0; i < 120; i++ {
+		ids = append(ids, fmt.Sprintf("video%06d", i))
+	}
+
+	urls := WatchVideosURLs(ids)
+	if len(urls) != 3 {
+		t.Fatalf("Expected 3 URLs, got %d", len(urls))
+	}
+
+	const prefix = "https://www.youtube.com/watch_videos?video_ids="
+	var joined []string
+	for i, url := range urls {
+		if !strings.HasPrefix(url, prefix) {
+			t.Fatalf("Expected URL %d to start with %s, got %s", i, prefix, url)
+		}
+		chunk := strings.Split(strings.TrimPrefix(url, prefix), ",")
+		if len(chunk) > MaxWatchVideos {
+			t.Errorf("Expected at most %d videos in URL %d, got %d", MaxWatchVideos, i, len(chunk))
+		}
+		joined = append(joined, chunk...)
+	}
+
+	if strings.Join(joined, ",") != strings.Join(ids, ",") {
+		t.Errorf("Expected the URLs to play every video in order, got %v", joined)
+	}
+}
+
+func TestWatchVideosURLsEmpty(t *testing.T) {
+	if urls := WatchVideosURLs(nil); len(urls) != 0 {
+		t.Errorf("Expected no URLs, got %v", urls)
+	}
+}
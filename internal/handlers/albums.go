@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"youtube-music-video-api/internal/services"
+)
+
+// maxAlbumTracks bounds how many searches a single album request may make.
+const maxAlbumTracks = 100
+
+type AlbumTrack struct {
+	Title    string   `json:"title"`
+	Artists  []string `json:"artists,omitempty"`
+	Duration int      `json:"duration,omitempty"` // seconds
+}
+
+type AlbumRequest struct {
+	Artist string       `json:"artist"`
+	Album  string       `json:"album"`
+	Tracks []AlbumTrack `json:"tracks"`
+}
+
+type AlbumTrackResult struct {
+	Position    int                         `json:"position"`
+	Title       string                      `json:"title"`
+	Artists     []string                    `json:"artists"`
+	Duration    int                         `json:"duration,omitempty"`
+	Video       *SearchVideo                `json:"video"`
+	Reason      string                      `json:"reason,omitempty"`
+	DuplicateOf int                         `json:"duplicateOf,omitempty"`
+	Skipped     []services.SkippedCandidate `json:"skipped,omitempty"`
+}
+
+type AlbumResponse struct {
+	Artist     string             `json:"artist"`
+	Album      string             `json:"album"`
+	Tracks     []AlbumTrackResult `json:"tracks"`
+	Resolved   int                `json:"resolved"`
+	Duplicates int                `json:"duplicates"`
+}
+
+// ResolveAlbumHandler godoc
+// @Summary Find music videos for every track on an album
+// @Description Resolves an ordered tracklist with the album title added to each search. A video is only used for one track; when a track has no other match, it keeps the video and duplicateOf names the track that already uses it. A track whose search fails has no video and a reason.
+// @Tags albums
+// @Accept json
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param album body AlbumRequest true "Album artist, title and ordered tracklist"
+// @Param region query string false "ISO 3166-1 alpha-2 region code, e.g. SE"
+// @Param lang query string false "Language code, e.g. sv or en-US"
+// @Param playable query bool false "Skip candidates that aren't playable in region"
+// @Param embeddable query bool false "Skip candidates with embedding disabled"
+// @Param exclude_age_restricted query bool false "Skip age-restricted candidates"
+// @Param duration_tolerance query int false "Allowed duration difference in seconds"
+// @Param strict_duration query bool false "Skip candidates outside the duration tolerance instead of ranking them lower"
+// @Param min_confidence query number false "Return no video unless a candidate's confidence, between 0 and 1, reaches this"
+// @Param format query string false "Response format when the Accept header can't be set: json, xml, yaml or msgpack"
+// @Success 200 {object} AlbumResponse
+// @Failure 400 {object} map[string]string
+// @Failure 406 {object} map[string]string
+// @Router /albums/resolve [post]
+func ResolveAlbumHandler(c *gin.Context) {
+	var request AlbumRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The body must be a JSON album with artist, album and tracks."})
+		return
+	}
+
+	request.Artist = strings.TrimSpace(request.Artist)
+	request.Album = strings.TrimSpace(request.Album)
+
+	if request.Artist == "" {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The artist can't be empty."})
+		return
+	}
+	if len(request.Tracks) == 0 {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The tracks can't be empty."})
+		return
+	}
+	if len(request.Tracks) > maxAlbumTracks {
+		respond(c, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("An album can have at most %d tracks.", maxAlbumTracks)})
+		return
+	}
+	for i, track := range request.Tracks {
+		if strings.TrimSpace(track.Title) == "" {
+			respond(c, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("The title of track %d can't be empty.", i+1)})
+			return
+		}
+		if track.Duration < 0 {
+			respond(c, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("The duration of track %d must be a number of seconds.", i+1)})
+			return
+		}
+	}
+
+	opts, err := parseSearchOptions(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	opts.Album = request.Album
+
+	response := AlbumResponse{
+		Artist: request.Artist,
+		Album:  request.Album,
+		Tracks: make([]AlbumTrackResult, 0, len(request.Tracks)),
+	}
+
+	// usedBy maps each chosen video ID to the position of its track.
+	usedBy := make(map[string]int)
+
+	for i, track := range request.Tracks {
+		result := AlbumTrackResult{
+			Position: i + 1,
+			Title:    strings.TrimSpace(track.Title),
+			Artists:  track.Artists,
+			Duration: track.Duration,
+		}
+		if len(result.Artists) == 0 {
+			result.Artists = []string{request.Artist}
+		}
+
+		trackOpts := opts
+		trackOpts.Duration = time.Duration(track.Duration) * time.Second
+
+		resolution, err := resolveAlbumTrack(result.Title, result.Artists, trackOpts, usedBy)
+		if err != nil {
+			log.Printf("Error searching YouTube for title '%s' with artists %v: %v", result.Title, result.Artists, err)
+			result.Reason = "Failed to search YouTube."
+			response.Tracks = append(response.Tracks, result)
+			continue
+		}
+
+		result.Video = newSearchVideo(resolution.Video)
+		result.Reason = resolution.Reason
+		result.Skipped = resolution.Skipped
+
+		if resolution.Video != nil {
+			response.Resolved++
+			if position, used := usedBy[resolution.Video.ID]; used {
+				result.DuplicateOf = position
+				response.Duplicates++
+			} else {
+				usedBy[resolution.Video.ID] = result.Position
+			}
+		}
+
+		response.Tracks = append(response.Tracks, result)
+	}
+
+	respond(c, http.StatusOK, response)
+}
+
+// resolveAlbumTrack resolves a track while avoiding the videos in usedBy.
+// When every match is already used, the unrestricted resolution is
+// returned so the caller can flag the duplicate.
+func resolveAlbumTrack(title string, artists []string, opts services.SearchOptions, usedBy map[string]int) (*services.Resolution, error) {
+	resolution, err := youtubeService.Resolve(title, artists, opts)
+	if err != nil || resolution.Video == nil {
+		return resolution, err
+	}
+	if _, used := usedBy[resolution.Video.ID]; !used {
+		return resolution, nil
+	}
+
+	for id := range usedBy {
+		opts.ExcludeIDs = append(opts.ExcludeIDs, id)
+	}
+
+	alternative, err := youtubeService.Resolve(title, artists, opts)
+	if err != nil {
+		return nil, err
+	}
+	if alternative.Video != nil {
+		return alternative, nil
+	}
+	return resolution, nil
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func renderer(id, title, channel string) string {
+	return `{"videoRenderer":{"videoId":"` + id + `","title":{"simpleText":"` + title + `"},"ownerText":{"runs":[{"text":"` + channel + `"}]}}}`
+}
+
+func TestResolveAlbumHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	pages := map[string][]string{
+		"Angels The xx Coexist": {renderer("angels", "The xx - Angels", "The xx")},
+		// Both versions of Chained list the same upload first.
+		"Chained The xx Coexist": {
+			renderer("chained", "The xx - Chained", "The xx"),
+			renderer("chained-live", "The xx - Chained (Live)", "The xx"),
+		},
+		"Chained (Reprise) The xx Coexist": {
+			renderer("chained", "The xx - Chained", "The xx"),
+			renderer("chained-reprise", "The xx - Chained Reprise", "The xx"),
+		},
+		"Angels (Edit) The xx Coexist": {renderer("angels", "The xx - Angels", "The xx")},
+	}
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("search_query")
+		renderers, ok := pages[query]
+		if !ok {
+			t.Errorf("Unexpected search query %q", query)
+		}
+		w.Write([]byte(`var ytInitialData = {"contents":[` + strings.Join(renderers, ",") + `]};`))
+	})
+
+	body := `{"artist":"The xx","album":"Coexist","tracks":[` +
+		`{"title":"Angels","duration":171},{"title":"Chained"},{"title":"Chained (Reprise)"},{"title":"Angels (Edit)"}]}`
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/albums/resolve", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	ResolveAlbumHandler(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response AlbumResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if len(response.Tracks) != 4 {
+		t.Fatalf("Expected 4 tracks, got %d", len(response.Tracks))
+	}
+
+	expected := []struct {
+		id          string
+		duplicateOf int
+	}{
+		{"angels", 0},
+		{"chained", 0},
+		{"chained-reprise", 0},
+		{"angels", 1},
+	}
+	for i, track := range response.Tracks {
+		if track.Position != i+1 {
+			t.Errorf("Expected position %d, got %d", i+1, track.Position)
+		}
+		if track.Video == nil || track.Video.ID != expected[i].id {
+			t.Errorf("Expected track %d to resolve to %s, got %+v", i+1, expected[i].id, track.Video)
+		}
+		if track.DuplicateOf != expected[i].duplicateOf {
+			t.Errorf("Expected track %d duplicateOf %d, got %d", i+1, expected[i].duplicateOf, track.DuplicateOf)
+		}
+	}
+
+	if response.Resolved != 4 || response.Duplicates != 1 {
+		t.Errorf("Expected 4 resolved and 1 duplicate, got %d and %d", response.Resolved, response.Duplicates)
+	}
+	if len(response.Tracks[0].Artists) != 1 || response.Tracks[0].Artists[0] != "The xx" {
+		t.Errorf("Expected the album artist to be used, got %v", response.Tracks[0].Artists)
+	}
+}
+
+func TestResolveAlbumHandler_FailedTrack(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("search_query") == "Chained The xx Coexist" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`var ytInitialData = {"contents":[` + renderer("angels", "The xx - Angels", "The xx") + `]};`))
+	})
+
+	body := `{"artist":"The xx","album":"Coexist","tracks":[{"title":"Chained"},{"title":"Angels"}]}`
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/albums/resolve", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	ResolveAlbumHandler(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response AlbumResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if len(response.Tracks) != 2 {
+		t.Fatalf("Expected 2 tracks, got %d", len(response.Tracks))
+	}
+	if track := response.Tracks[0]; track.Video != nil || track.Reason != "Failed to search YouTube." {
+		t.Errorf("Expected the failed search to be reported on its track, got %+v", track)
+	}
+	if track := response.Tracks[1]; track.Video == nil || track.Video.ID != "angels" {
+		t.Errorf("Expected the next track to still resolve, got %+v", track)
+	}
+	if response.Resolved != 1 {
+		t.Errorf("Expected 1 resolved track, got %d", response.Resolved)
+	}
+}
+
+func TestResolveAlbumHandler_InvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []string{
+		`not json`,
+		`{"album":"Coexist","tracks":[{"title":"Angels"}]}`,
+		`{"artist":"The xx","album":"Coexist","tracks":[]}`,
+		`{"artist":"The xx","album":"Coexist","tracks":[{"title":" "}]}`,
+		`{"artist":"The xx","album":"Coexist","tracks":[{"title":"Angels","duration":-1}]}`,
+	}
+
+	for _, body := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/albums/resolve", strings.NewReader(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		ResolveAlbumHandler(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, body, w.Code)
+		}
+	}
+}
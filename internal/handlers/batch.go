@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"youtube-music-video-api/internal/importers"
+	"youtube-music-video-api/internal/services"
+)
+
+// maxConcurrentResolves bounds how many tracks of one batch are searched for
+// at the same time.
+const maxConcurrentResolves = 4
+
+// TrackResult is the outcome of resolving one entry of an imported
+// playlist or library.
+type TrackResult struct {
+	Position int          `json:"position"`
+	Title    string       `json:"title"`
+	Artists  []string     `json:"artists,omitempty"`
+	Album    string       `json:"album,omitempty"`
+	Duration int          `json:"duration,omitempty"`
+	Video    *SearchVideo `json:"video"`
+	Reason   string       `json:"reason,omitempty"`
+}
+
+// resolveTracks resolves every track with opts, using each track's own
+// duration whenever it has one. Results keep the order of tracks; a failed
+// search only affects its own entry.
+func resolveTracks(tracks []importers.Track, opts services.SearchOptions) []TrackResult {
+	results := make([]TrackResult, len(tracks))
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, maxConcurrentResolves)
+
+	for i, track := range tracks {
+		results[i] = TrackResult{
+			Position: i + 1,
+			Title:    track.Title,
+			Artists:  track.Artists,
+			Album:    track.Album,
+			Duration: track.Duration,
+		}
+
+		if track.Title == "" {
+			results[i].Reason = "The entry has no title."
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *TrackResult) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			// A track's own duration beats the request's, which only
+			// applies to tracks without one.
+			trackOpts := opts
+			if result.Duration > 0 {
+				trackOpts.Duration = time.Duration(result.Duration) * time.Second
+			}
+
+			resolution, err := youtubeService.Resolve(result.Title, result.Artists, trackOpts)
+			if err != nil {
+				log.Printf("Error searching YouTube for title '%s' with artists %v: %v", result.Title, result.Artists, err)
+				result.Reason = "Failed to search YouTube."
+				return
+			}
+
+			result.Video = newSearchVideo(resolution.Video)
+			result.Reason = resolution.Reason
+		}(&results[i])
+	}
+
+	wg.Wait()
+	return results
+}
+
+// countResolved returns how many results have a video.
+func countResolved(results []TrackResult) int {
+	resolved := 0
+	for _, result := range results {
+		if result.Video != nil {
+			resolved++
+		}
+	}
+	return resolved
+}
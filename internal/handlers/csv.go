@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"youtube-music-video-api/internal/importers"
+	"youtube-music-video-api/internal/services"
+)
+
+// csvResultColumns are appended to every row of a resolved CSV.
+var csvResultColumns = []string{"video_id", "video_url", "confidence", "status"}
+
+// Values of the status column of a resolved CSV.
+const (
+	csvStatusMatched       = "matched"
+	csvStatusLowConfidence = "low_confidence"
+	csvStatusNoMatch       = "no_match"
+	csvStatusNotFound      = "not_found"
+	csvStatusSkipped       = "skipped"
+	csvStatusError         = "error"
+)
+
+// ImportCSVHandler godoc
+// @Summary Find music videos for every row of a CSV
+// @Description Resolves each row of a CSV with a header row and streams the same CSV back with video_id, video_url, confidence and status columns appended. Rows without a title are looked up by their ISRC, MusicBrainz ID or Spotify URI. The status is matched, low_confidence, no_match, not_found, skipped or error. When the input can't be read to the end, a last row with status error explains why in its first column.
+// @Tags import
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce text/csv
+// @Param file formData file false "CSV file, when uploading as multipart/form-data instead of sending it as the body"
+// @Param title_column query string false "Header of the title column, default title, track, song or name"
+// @Param artists_column query string false "Header of the artists column, default artists or artist"
+// @Param duration_column query string false "Header of the duration column (seconds or mm:ss), default duration or length"
+// @Param id_column query string false "Header of the external ID column, default id, external_id or isrc"
+// @Param delimiter query string false "Field delimiter: comma, semicolon or tab"
+// @Param region query string false "ISO 3166-1 alpha-2 region code, e.g. SE"
+// @Param lang query string false "Language code, e.g. sv or en-US"
+// @Param playable query bool false "Skip candidates that aren't playable in region"
+// @Param embeddable query bool false "Skip candidates with embedding disabled"
+// @Param exclude_age_restricted query bool false "Skip age-restricted candidates"
+// @Param duration_tolerance query int false "Allowed duration difference in seconds"
+// @Param strict_duration query bool false "Skip candidates outside the duration tolerance instead of ranking them lower"
+// @Param min_confidence query number false "Return no video unless a candidate's confidence, between 0 and 1, reaches this"
+// @Success 200 {string} string "The CSV with result columns appended"
+// @Failure 400 {object} map[string]string
+// @Router /import/csv [post]
+func ImportCSVHandler(c *gin.Context) {
+	delimiter, err := parseDelimiter(c.Query("delimiter"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	opts, err := parseSearchOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	input, err := csvInput(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	columns := importers.CSVColumns{
+		Title:    c.Query("title_column"),
+		Artists:  c.Query("artists_column"),
+		Duration: c.Query("duration_column"),
+		ID:       c.Query("id_column"),
+	}
+	reader, err := importers.NewCSVReader(input, columns, delimiter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, map[string]string{"error": "The CSV couldn't be read: " + err.Error() + "."})
+		return
+	}
+
+	// Rows are written while the body is still being read. Without full
+	// duplex, an HTTP/1.1 server discards the unread body on the first flush
+	// and the import ends early. HTTP/2 doesn't need it and reports it as
+	// unsupported.
+	if err := http.NewResponseController(c.Writer).EnableFullDuplex(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Printf("Error enabling full duplex for CSV import: %v", err)
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="resolved.csv"`)
+	c.Status(http.StatusOK)
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Comma = delimiter
+	width := len(reader.Header())
+	writer.Write(append(reader.Header(), csvResultColumns...))
+
+	// Rows are resolved concurrently but written in input order: each row
+	// queues a channel for its result, and at most maxConcurrentResolves
+	// rows are in flight so large files are never held in memory.
+	pending := make(chan chan []string, maxConcurrentResolves)
+	ctx := c.Request.Context()
+
+	go func() {
+		defer close(pending)
+		for ctx.Err() == nil {
+			row, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				// The status line is long gone, so the failure is reported
+				// in a last row rather than leaving a CSV that looks complete.
+				log.Printf("Error reading CSV: %v", err)
+				result := make(chan []string, 1)
+				result <- csvReadErrorRow(width, err)
+				select {
+				case pending <- result:
+				case <-ctx.Done():
+				}
+				return
+			}
+
+			result := make(chan []string, 1)
+			select {
+			case pending <- result:
+			case <-ctx.Done():
+				return
+			}
+
+			go func() {
+				record := make([]string, max(width, len(row.Record)))
+				copy(record, row.Record)
+				result <- append(record, resolveCSVRow(row, opts)...)
+			}()
+		}
+	}()
+
+	for result := range pending {
+		writer.Write(<-result)
+		writer.Flush()
+		c.Writer.Flush()
+	}
+}
+
+// resolveCSVRow returns the values of csvResultColumns for row.
+func resolveCSVRow(row *importers.CSVRow, opts services.SearchOptions) []string {
+	track := row.Track
+
+	if track.Title == "" {
+		if row.ExternalID == "" {
+			return []string{"", "", "", csvStatusSkipped}
+		}
+
+		recording, err := lookupExternalID(row.ExternalID)
+		if errors.Is(err, services.ErrRecordingNotFound) {
+			return []string{"", "", "", csvStatusNotFound}
+		}
+		if err != nil {
+			log.Printf("Error looking up %s: %v", row.ExternalID, err)
+			return []string{"", "", "", csvStatusError}
+		}
+
+		track = importers.Track{Title: recording.Title, Artists: recording.Artists, Duration: recording.Duration}
+	}
+
+	if track.Duration > 0 {
+		opts.Duration = time.Duration(track.Duration) * time.Second
+	}
+
+	resolution, err := youtubeService.Resolve(track.Title, track.Artists, opts)
+	if err != nil {
+		log.Printf("Error searching YouTube for title '%s' with artists %v: %v", track.Title, track.Artists, err)
+		return []string{"", "", "", csvStatusError}
+	}
+
+	video := resolution.Video
+	if video == nil {
+		if len(resolution.LowConfidence) > 0 {
+			return []string{"", "", "", csvStatusLowConfidence}
+		}
+		return []string{"", "", "", csvStatusNoMatch}
+	}
+
+	confidence := ""
+	if video.Confidence != nil {
+		confidence = fmt.Sprintf("%.3f", *video.Confidence)
+	}
+	return []string{video.ID, newSearchVideo(video).URL, confidence, csvStatusMatched}
+}
+
+// csvReadErrorRow is the last row of a resolved CSV whose input couldn't be
+// read to the end. The first column explains why and the status is error.
+func csvReadErrorRow(width int, err error) []string {
+	record := make([]string, max(width, 1))
+	record[0] = "The rest of the CSV couldn't be read: " + err.Error()
+	return append(record, "", "", "", csvStatusError)
+}
+
+// csvInput returns the CSV from the multipart field named file, or the
+// request body when it isn't a multipart upload. Neither is buffered in
+// full.
+func csvInput(c *gin.Context) (io.Reader, error) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			return nil, errors.New("Send the CSV as the request body or as a multipart form field named file.")
+		}
+		return c.Request.Body, nil
+	}
+
+	parts, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, errors.New("The multipart upload couldn't be read.")
+	}
+	for {
+		part, err := parts.NextPart()
+		if err != nil {
+			return nil, errors.New("Upload the CSV as a multipart form field named file.")
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+	}
+}
+
+func parseDelimiter(value string) (rune, error) {
+	switch strings.ToLower(value) {
+	case "", ",", "comma":
+		return ',', nil
+	case ";", "semicolon":
+		return ';', nil
+	case "\t", "tab":
+		return '\t', nil
+	}
+	return 0, errors.New("The delimiter must be comma, semicolon or tab.")
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/gin-gonic/gin"
+	"youtube-music-video-api/internal/services"
+)
+
+func TestImportCSVHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	videos := map[string]string{
+		"Euphoria Loreen":                     "euphoria",
+		"Never Gonna Give You Up Rick Astley": "dQw4w9WgXcQ",
+	}
+	body := "Song,Performer,Catalog ID\n" +
+		"Euphoria,Loreen,A1\n" +
+		"Unknown Song,Nobody,A2\n" +
+		",,USRC17607839\n" +
+		",,\n"
+	// Enough extra rows to exercise concurrent resolution and ordering.
+	for i := range 12 {
+		title := fmt.Sprintf("Track %d", i)
+		videos[title+" Band"] = fmt.Sprintf("track%07d", i)
+		body += title + ",Band,B" + fmt.Sprint(i) + "\n"
+	}
+
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("search_query")
+		if id, ok := videos[query]; ok {
+			w.Write([]byte(`var ytInitialData = {"contents":[` + renderer(id, query, "") + `]};`))
+			return
+		}
+		w.Write([]byte(`var ytInitialData = {"contents":[` + renderer("unrelated", "Completely Different Thing", "Someone") + `]};`))
+	})
+	useStubMetadata(t, &stubMetadataProvider{isrcs: map[string]*services.Recording{
+		"USRC17607839": {Title: "Never Gonna Give You Up", Artists: []string{"Rick Astley"}},
+	}})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/import/csv?title_column=Song&artists_column=Performer&id_column=Catalog+ID&min_confidence=0.5", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "text/csv")
+
+	ImportCSVHandler(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/csv") {
+		t.Errorf("Expected a CSV response, got %q", contentType)
+	}
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse response CSV: %v", err)
+	}
+	if len(records) != 17 {
+		t.Fatalf("Expected header and 16 rows, got %d records", len(records))
+	}
+
+	expectedHeader := []string{"Song", "Performer", "Catalog ID", "video_id", "video_url", "confidence", "status"}
+	if !reflect.DeepEqual(records[0], expectedHeader) {
+		t.Errorf("Expected header %v, got %v", expectedHeader, records[0])
+	}
+
+	if row := records[1]; row[3] != "euphoria" || row[4] != "https://www.youtube.com/watch?v=euphoria" || row[5] == "" || row[6] != "matched" {
+		t.Errorf("Unexpected matched row %v", row)
+	}
+	if row := records[2]; row[3] != "" || row[6] != "low_confidence" {
+		t.Errorf("Unexpected unmatched row %v", row)
+	}
+	if row := records[3]; row[3] != "dQw4w9WgXcQ" || row[6] != "matched" {
+		t.Errorf("Expected the ISRC row to be looked up, got %v", row)
+	}
+	if row := records[4]; row[6] != "skipped" {
+		t.Errorf("Expected the empty row to be skipped, got %v", row)
+	}
+	for i := range 12 {
+		if row := records[5+i]; row[2] != "B"+fmt.Sprint(i) || row[3] != fmt.Sprintf("track%07d", i) {
+			t.Errorf("Expected row %d in input order, got %v", 5+i, row)
+		}
+	}
+}
+
+func TestImportCSVHandler_Multipart(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	stubSearchResults(t, map[string]string{"Euphoria Loreen": "euphoria"})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = uploadRequest(t, "/import/csv?delimiter=semicolon", "tracks.csv", "title;artist\nEuphoria;Loreen\n")
+
+	ImportCSVHandler(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "Euphoria;Loreen;euphoria;") {
+		t.Errorf("Expected a semicolon-separated result row, got %q", w.Body.String())
+	}
+}
+
+func TestImportCSVHandler_RowDurationWins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	stubTwoLengths(t)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/import/csv?duration=180", strings.NewReader("title,artist,duration\nEuphoria,Loreen,5:00\nGruppa Krovi,Kino,\n"))
+	c.Request.Header.Set("Content-Type", "text/csv")
+
+	ImportCSVHandler(c)
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse response CSV: %v", err)
+	}
+	if len(records) != 3 || records[1][3] != "long" || records[2][3] != "short" {
+		t.Errorf("Expected the row's own duration to win over the request's, got %v", records)
+	}
+}
+
+func TestImportCSVHandler_ReadErrorAfterHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := io.MultiReader(strings.NewReader("title,artist\n,Loreen\n"), iotest.ErrReader(errors.New("connection reset")))
+	c.Request = httptest.NewRequest("POST", "/import/csv", body)
+	c.Request.Header.Set("Content-Type", "text/csv")
+
+	ImportCSVHandler(c)
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse response CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected header, the row read and an error row, got %v", records)
+	}
+	if last := records[2]; !strings.Contains(last[0], "connection reset") || last[5] != "error" {
+		t.Errorf("Expected a trailing error row, got %v", last)
+	}
+}
+
+func TestImportCSVHandler_InvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		target string
+		body   string
+	}{
+		{"/import/csv", ""},
+		{"/import/csv", "artist\nLoreen\n"},
+		{"/import/csv?title_column=Song", "title\nEuphoria\n"},
+		{"/import/csv?delimiter=pipe", "title\nEuphoria\n"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", tt.target, strings.NewReader(tt.body))
+		c.Request.Header.Set("Content-Type", "text/csv")
+
+		ImportCSVHandler(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s %q, got %d", http.StatusBadRequest, tt.target, tt.body, w.Code)
+		}
+	}
+}
+
+func TestImportCSVHandler_LargeBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The server reads ahead at most 4 KB of the body, so rows past that are
+	// only seen if the body is still readable after the first flush.
+	const rows = 2000
+	var body strings.Builder
+	body.WriteString("title,artist\n")
+	for i := range rows {
+		fmt.Fprintf(&body, ",Artist without a title %04d\n", i)
+	}
+
+	router := gin.New()
+	router.POST("/import/csv", ImportCSVHandler)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/import/csv", "text/csv", strings.NewReader(body.String()))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to parse response CSV: %v", err)
+	}
+	if len(records) != rows+1 {
+		t.Fatalf("Expected header and %d rows, got %d records", rows, len(records))
+	}
+	if row := records[rows]; row[1] != fmt.Sprintf("Artist without a title %04d", rows-1) || row[5] != "skipped" {
+		t.Errorf("Unexpected last row %v", row)
+	}
+}
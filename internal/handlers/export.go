@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"youtube-music-video-api/internal/exporters"
+	"youtube-music-video-api/internal/services"
+)
+
+// maxExportVideos is the most videos a YouTube playlist can hold.
+const maxExportVideos = 5000
+
+type ExportRequest struct {
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Privacy is private (default), unlisted or public.
+	Privacy string            `json:"privacy,omitempty"`
+	Videos  []exporters.Video `json:"videos"`
+}
+
+type WatchVideosResponse struct {
+	URLs   []string `json:"urls"`
+	Videos int      `json:"videos"`
+}
+
+// ExportWatchVideosHandler godoc
+// @Summary Export videos as anonymous YouTube playlists
+// @Description Returns watch_videos URLs that play the videos in order without signing in. YouTube plays at most 50 videos per URL, so longer lists are split over several URLs.
+// @Tags export
+// @Accept json
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param playlist body ExportRequest true "Ordered videos, e.g. the video of each entry of an import"
+// @Param format query string false "Response format when the Accept header can't be set: json, xml, yaml or msgpack"
+// @Success 200 {object} WatchVideosResponse
+// @Failure 400 {object} map[string]string
+// @Failure 406 {object} map[string]string
+// @Router /export/watch-videos [post]
+func ExportWatchVideosHandler(c *gin.Context) {
+	request, ok := bindExportRequest(c)
+	if !ok {
+		return
+	}
+
+	ids := make([]string, 0, len(request.Videos))
+	for _, video := range request.Videos {
+		ids = append(ids, video.ID)
+	}
+
+	respond(c, http.StatusOK, WatchVideosResponse{URLs: exporters.WatchVideosURLs(ids), Videos: len(ids)})
+}
+
+// ExportM3UHandler godoc
+// @Summary Export videos as an M3U playlist
+// @Description Returns an extended M3U playlist of watch URLs for players that stream YouTube, such as VLC or mpv
+// @Tags export
+// @Accept json
+// @Produce audio/x-mpegurl
+// @Param playlist body ExportRequest true "Ordered videos, e.g. the video of each entry of an import"
+// @Success 200 {string} string
+// @Failure 400 {object} map[string]string
+// @Router /export/m3u [post]
+func ExportM3UHandler(c *gin.Context) {
+	request, ok := bindExportRequest(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="playlist.m3u8"`)
+	c.Header("Content-Type", "audio/x-mpegurl; charset=utf-8")
+	c.Status(http.StatusOK)
+	exporters.WriteM3U(c.Writer, request.Title, request.Videos)
+}
+
+// ExportJSONHandler godoc
+// @Summary Export videos for scripted playlist creation
+// @Description Returns the YouTube Data API resources that create the playlist: send playlist to playlists.insert, then each of items to playlistItems.insert with snippet.playlistId set to the new playlist's ID
+// @Tags export
+// @Accept json
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param playlist body ExportRequest true "Ordered videos, e.g. the video of each entry of an import"
+// @Param format query string false "Response format when the Accept header can't be set: json, xml, yaml or msgpack"
+// @Success 200 {object} exporters.PlaylistDocument
+// @Failure 400 {object} map[string]string
+// @Failure 406 {object} map[string]string
+// @Router /export/json [post]
+func ExportJSONHandler(c *gin.Context) {
+	request, ok := bindExportRequest(c)
+	if !ok {
+		return
+	}
+
+	if request.Title == "" {
+		request.Title = "Music videos"
+	}
+	respond(c, http.StatusOK, exporters.NewPlaylistDocument(request.Title, request.Description, request.Privacy, request.Videos))
+}
+
+// bindExportRequest reads and validates the body of an export request,
+// responding with an error and returning false when it isn't usable.
+func bindExportRequest(c *gin.Context) (ExportRequest, bool) {
+	var request ExportRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The body must be a JSON object with a videos list."})
+		return request, false
+	}
+
+	request.Title = strings.TrimSpace(request.Title)
+	request.Description = strings.TrimSpace(request.Description)
+
+	switch request.Privacy = strings.ToLower(strings.TrimSpace(request.Privacy)); request.Privacy {
+	case "":
+		request.Privacy = "private"
+	case "private", "unlisted", "public":
+	default:
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The privacy must be private, unlisted or public."})
+		return request, false
+	}
+
+	if len(request.Videos) == 0 {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The videos can't be empty."})
+		return request, false
+	}
+	if len(request.Videos) > maxExportVideos {
+		respond(c, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("A playlist can have at most %d videos.", maxExportVideos)})
+		return request, false
+	}
+	for i, video := range request.Videos {
+		if !services.IsVideoID(video.ID) {
+			respond(c, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("The id of video %d must be an 11-character YouTube video ID.", i+1)})
+			return request, false
+		}
+	}
+
+	return request, true
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"youtube-music-video-api/internal/exporters"
+)
+
+func exportRequest(t *testing.T, handler gin.HandlerFunc, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", target, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	handler(c)
+	return w
+}
+
+func TestExportWatchVideosHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := exportRequest(t, ExportWatchVideosHandler, "/export/watch-videos",
+		`{"videos":[{"id":"dQw4w9WgXcQ","title":"Never Gonna Give You Up"},{"id":"Pfhpe6shO2U"}]}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response WatchVideosResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	expected := "https://www.youtube.com/watch_videos?video_ids=dQw4w9WgXcQ,Pfhpe6shO2U"
+	if len(response.URLs) != 1 || response.URLs[0] != expected {
+		t.Errorf("Expected URLs [%s], got %v", expected, response.URLs)
+	}
+	if response.Videos != 2 {
+		t.Errorf("Expected 2 videos, got %d", response.Videos)
+	}
+}
+
+func TestExportM3UHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := exportRequest(t, ExportM3UHandler, "/export/m3u",
+		`{"title":"Road trip","videos":[{"id":"dQw4w9WgXcQ","title":"Never Gonna Give You Up","duration":213}]}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "audio/x-mpegurl") {
+		t.Errorf("Expected an M3U content type, got %s", contentType)
+	}
+	if !strings.Contains(w.Body.String(), "#EXTINF:213,Never Gonna Give You Up\nhttps://www.youtube.com/watch?v=dQw4w9WgXcQ\n") {
+		t.Errorf("Expected the video in the playlist, got:\n%s", w.Body.String())
+	}
+}
+
+func TestExportJSONHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := exportRequest(t, ExportJSONHandler, "/export/json", `{"videos":[{"id":"dQw4w9WgXcQ"}]}`)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var document exporters.PlaylistDocument
+	if err := json.Unmarshal(w.Body.Bytes(), &document); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if document.Playlist.Snippet.Title != "Music videos" {
+		t.Errorf("Expected the default title, got %q", document.Playlist.Snippet.Title)
+	}
+	if document.Playlist.Status.PrivacyStatus != "private" {
+		t.Errorf("Expected privacy status private, got %q", document.Playlist.Status.PrivacyStatus)
+	}
+	if len(document.Items) != 1 || document.Items[0].Snippet.ResourceID.VideoID != "dQw4w9WgXcQ" {
+		t.Errorf("Expected one item for dQw4w9WgXcQ, got %+v", document.Items)
+	}
+}
+
+func TestExportHandlerInvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"not JSON", `videos`},
+		{"no videos", `{"videos":[]}`},
+		{"invalid ID", `{"videos":[{"id":"dQw4w9WgXcQ"},{"id":"https://youtu.be/x"}]}`},
+		{"invalid privacy", `{"privacy":"friends","videos":[{"id":"dQw4w9WgXcQ"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := exportRequest(t, ExportJSONHandler, "/export/json", tt.body)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
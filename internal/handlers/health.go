@@ -4,10 +4,12 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"youtube-music-video-api/internal/services"
 )
 
 type HealthResponse struct {
-	Status string `json:"status"`
+	Status  string                `json:"status"`
+	Proxies []services.ProxyStats `json:"proxies,omitempty"`
 }
 
 // HealthHandler godoc
@@ -18,5 +20,8 @@ type HealthResponse struct {
 // @Success 200 {object} HealthResponse
 // @Router /health [get]
 func HealthHandler(c *gin.Context) {
-	c.JSON(http.StatusOK, HealthResponse{Status: "ok"})
+	c.JSON(http.StatusOK, HealthResponse{
+		Status:  "ok",
+		Proxies: youtubeService.ProxyStats(),
+	})
 }
\ No newline at end of file
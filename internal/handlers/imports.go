@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"youtube-music-video-api/internal/importers"
+)
+
+const (
+	// maxImportSize is the largest file accepted for import, except for
+	// iTunes libraries, which list every track with all its metadata and
+	// are allowed up to maxLibrarySize.
+	maxImportSize  = 10 << 20
+	maxLibrarySize = 200 << 20
+
+	// multipartOverhead allows for the boundaries and part headers around
+	// an uploaded file.
+	multipartOverhead = 64 << 10
+
+	// maxImportEntries bounds how many searches a single import may make.
+	maxImportEntries = 500
+)
+
+type ImportResponse struct {
+	Format   string        `json:"format"`
+	Total    int           `json:"total"`
+	Resolved int           `json:"resolved"`
+	Entries  []TrackResult `json:"entries"`
+}
+
+// ImportPlaylistHandler godoc
+// @Summary Find music videos for a playlist file
+// @Description Parses an uploaded M3U/M3U8, XSPF or PLS playlist and resolves every entry to a music video
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param file formData file true "M3U, M3U8, XSPF or PLS playlist"
+// @Param region query string false "ISO 3166-1 alpha-2 region code, e.g. SE"
+// @Param lang query string false "Language code, e.g. sv or en-US"
+// @Param playable query bool false "Skip candidates that aren't playable in region"
+// @Param embeddable query bool false "Skip candidates with embedding disabled"
+// @Param exclude_age_restricted query bool false "Skip age-restricted candidates"
+// @Param duration_tolerance query int false "Allowed duration difference in seconds"
+// @Param strict_duration query bool false "Skip candidates outside the duration tolerance instead of ranking them lower"
+// @Param min_confidence query number false "Return no video unless a candidate's confidence, between 0 and 1, reaches this"
+// @Param format query string false "Response format when the Accept header can't be set: json, xml, yaml or msgpack"
+// @Success 200 {object} ImportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 406 {object} map[string]string
+// @Router /import/playlist [post]
+func ImportPlaylistHandler(c *gin.Context) {
+	filename, data, err := readUpload(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	opts, err := parseSearchOptions(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	format, tracks, err := importers.ParsePlaylist(filename, data)
+	if errors.Is(err, importers.ErrUnknownFormat) {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The file must be an M3U, M3U8, XSPF or PLS playlist."})
+		return
+	}
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The playlist couldn't be parsed."})
+		return
+	}
+
+	if len(tracks) > maxImportEntries {
+		respond(c, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("A playlist can have at most %d entries.", maxImportEntries)})
+		return
+	}
+
+	entries := resolveTracks(tracks, opts)
+	respond(c, http.StatusOK, ImportResponse{
+		Format:   format,
+		Total:    len(entries),
+		Resolved: countResolved(entries),
+		Entries:  entries,
+	})
+}
+
+type ITunesPlaylistResult struct {
+	Name    string        `json:"name"`
+	Entries []TrackResult `json:"entries"`
+}
+
+type ITunesImportResponse struct {
+	Total     int                    `json:"total"`
+	Resolved  int                    `json:"resolved"`
+	Playlists []ITunesPlaylistResult `json:"playlists"`
+	Unlisted  []TrackResult          `json:"unlisted,omitempty"`
+}
+
+// ImportITunesHandler godoc
+// @Summary Find music videos for an iTunes library
+// @Description Parses an uploaded iTunes or Apple Music Library.xml and resolves its tracks to music videos, grouped by playlist. Each track is searched for once, however many playlists it is in.
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param file formData file true "iTunes Library.xml"
+// @Param playlist query []string false "Only import these playlists; by default every playlist and the tracks in none of them are imported" collectionFormat(multi)
+// @Param region query string false "ISO 3166-1 alpha-2 region code, e.g. SE"
+// @Param lang query string false "Language code, e.g. sv or en-US"
+// @Param playable query bool false "Skip candidates that aren't playable in region"
+// @Param embeddable query bool false "Skip candidates with embedding disabled"
+// @Param exclude_age_restricted query bool false "Skip age-restricted candidates"
+// @Param duration_tolerance query int false "Allowed duration difference in seconds"
+// @Param strict_duration query bool false "Skip candidates outside the duration tolerance instead of ranking them lower"
+// @Param min_confidence query number false "Return no video unless a candidate's confidence, between 0 and 1, reaches this"
+// @Param format query string false "Response format when the Accept header can't be set: json, xml, yaml or msgpack"
+// @Success 200 {object} ITunesImportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 406 {object} map[string]string
+// @Router /import/itunes [post]
+func ImportITunesHandler(c *gin.Context) {
+	opts, err := parseSearchOptions(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_, file, err := openUpload(c, maxLibrarySize)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	library, err := importers.ParseITunesLibrary(file)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The file must be an iTunes Library.xml."})
+		return
+	}
+
+	// Pick the playlists to import and the library tracks they need, each
+	// track only once.
+	selected := c.QueryArray("playlist")
+	var playlists []importers.ITunesPlaylist
+	for _, playlist := range library.Playlists {
+		if len(selected) == 0 || slices.Contains(selected, playlist.Name) {
+			playlists = append(playlists, playlist)
+		}
+	}
+	if len(selected) > 0 && len(playlists) == 0 {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "None of the requested playlists are in the library."})
+		return
+	}
+
+	var tracks []importers.Track
+	positions := make(map[int]int) // library index to index in tracks
+	include := func(index int) {
+		if _, ok := positions[index]; !ok {
+			positions[index] = len(tracks)
+			tracks = append(tracks, library.Tracks[index])
+		}
+	}
+	for _, playlist := range playlists {
+		for _, index := range playlist.Tracks {
+			include(index)
+		}
+	}
+	listed := len(tracks)
+	if len(selected) == 0 {
+		for index := range library.Tracks {
+			include(index)
+		}
+	}
+
+	if len(tracks) > maxImportEntries {
+		respond(c, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("At most %d tracks can be imported at once; choose playlists with the playlist parameter.", maxImportEntries)})
+		return
+	}
+
+	results := resolveTracks(tracks, opts)
+
+	response := ITunesImportResponse{
+		Total:     len(results),
+		Resolved:  countResolved(results),
+		Playlists: make([]ITunesPlaylistResult, 0, len(playlists)),
+	}
+	for _, playlist := range playlists {
+		entries := make([]TrackResult, 0, len(playlist.Tracks))
+		for i, index := range playlist.Tracks {
+			entry := results[positions[index]]
+			entry.Position = i + 1
+			entries = append(entries, entry)
+		}
+		response.Playlists = append(response.Playlists, ITunesPlaylistResult{Name: playlist.Name, Entries: entries})
+	}
+	for i, result := range results[listed:] {
+		result.Position = i + 1
+		response.Unlisted = append(response.Unlisted, result)
+	}
+
+	respond(c, http.StatusOK, response)
+}
+
+const (
+	defaultTopTracks = 25
+	maxTopTracks     = 100
+)
+
+type RankedTrackResult struct {
+	TrackResult
+	Plays int `json:"plays"`
+}
+
+type ScrobbleImportResponse struct {
+	Source   string              `json:"source"`
+	Listens  int                 `json:"listens"`
+	Tracks   int                 `json:"tracks"`
+	Resolved int                 `json:"resolved"`
+	Entries  []RankedTrackResult `json:"entries"`
+}
+
+// ImportScrobblesHandler godoc
+// @Summary Find music videos for the most played songs of a listening history
+// @Description Counts the listens in an uploaded Last.fm scrobble CSV or ListenBrainz JSON export by song, and resolves the most played songs to music videos
+// @Tags import
+// @Accept multipart/form-data
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param file formData file true "Last.fm CSV or ListenBrainz JSON/JSONL export"
+// @Param limit query int false "Number of songs to resolve, 1 to 100, default 25"
+// @Param region query string false "ISO 3166-1 alpha-2 region code, e.g. SE"
+// @Param lang query string false "Language code, e.g. sv or en-US"
+// @Param playable query bool false "Skip candidates that aren't playable in region"
+// @Param embeddable query bool false "Skip candidates with embedding disabled"
+// @Param exclude_age_restricted query bool false "Skip age-restricted candidates"
+// @Param min_confidence query number false "Return no video unless a candidate's confidence, between 0 and 1, reaches this"
+// @Param format query string false "Response format when the Accept header can't be set: json, xml, yaml or msgpack"
+// @Success 200 {object} ScrobbleImportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 406 {object} map[string]string
+// @Router /import/scrobbles [post]
+func ImportScrobblesHandler(c *gin.Context) {
+	limit := defaultTopTracks
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxTopTracks {
+			respond(c, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("The limit must be a number between 1 and %d.", maxTopTracks)})
+			return
+		}
+		limit = parsed
+	}
+
+	opts, err := parseSearchOptions(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	_, file, err := openUpload(c, maxLibrarySize)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	source := importers.DetectScrobbleSource(reader)
+	counter := importers.NewPlayCounter()
+
+	if source == importers.SourceListenBrainz {
+		err = importers.ReadListenBrainzListens(reader, counter.Add)
+	} else {
+		err = importers.ReadLastFMScrobbles(reader, counter.Add)
+	}
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The file must be a Last.fm scrobble CSV or a ListenBrainz JSON export."})
+		return
+	}
+
+	top := counter.Top(limit)
+	tracks := make([]importers.Track, 0, len(top))
+	for _, count := range top {
+		tracks = append(tracks, count.Track)
+	}
+	results := resolveTracks(tracks, opts)
+
+	response := ScrobbleImportResponse{
+		Source:   source,
+		Listens:  counter.Listens(),
+		Tracks:   counter.Tracks(),
+		Resolved: countResolved(results),
+		Entries:  make([]RankedTrackResult, 0, len(results)),
+	}
+	for i, result := range results {
+		response.Entries = append(response.Entries, RankedTrackResult{TrackResult: result, Plays: top[i].Plays})
+	}
+
+	respond(c, http.StatusOK, response)
+}
+
+// readUpload returns the name and contents of the multipart file field
+// named file. Errors are suitable for returning to the client.
+func readUpload(c *gin.Context) (string, []byte, error) {
+	filename, file, err := openUpload(c, maxImportSize)
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImportSize))
+	if err != nil {
+		return "", nil, errors.New("The uploaded file couldn't be read.")
+	}
+
+	return filename, data, nil
+}
+
+// openUpload opens the multipart file field named file, rejecting files
+// larger than maxSize. The body is limited before it is parsed, so an
+// oversized upload is never read in full. Errors are suitable for returning
+// to the client.
+func openUpload(c *gin.Context, maxSize int64) (string, io.ReadCloser, error) {
+	tooLarge := fmt.Errorf("The file can be at most %d MB.", maxSize>>20)
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+	header, err := c.FormFile("file")
+	if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+		return "", nil, tooLarge
+	}
+	if err != nil {
+		return "", nil, errors.New("Upload the file as a multipart form field named file.")
+	}
+	if header.Size > maxSize {
+		return "", nil, tooLarge
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return "", nil, errors.New("The uploaded file couldn't be read.")
+	}
+
+	return header.Filename, file, nil
+}
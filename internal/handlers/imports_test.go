@@ -0,0 +1,410 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// uploadRequest builds a multipart request carrying content as the file
+// field named file.
+func uploadRequest(t *testing.T, target, filename, content string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatalf("Failed to create form file: %v", err)
+	}
+	part.Write([]byte(content))
+	writer.Close()
+
+	req := httptest.NewRequest("POST", target, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// stubSearchResults serves one search result per query, titled after the
+// query itself, so every entry resolves to a predictable video.
+func stubSearchResults(t *testing.T, videos map[string]string) {
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("search_query")
+		id, ok := videos[query]
+		if !ok {
+			w.Write([]byte(`var ytInitialData = {"contents":[]};`))
+			return
+		}
+		w.Write([]byte(`var ytInitialData = {"contents":[` + renderer(id, query, "") + `]};`))
+	})
+}
+
+func TestImportPlaylistHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	stubSearchResults(t, map[string]string{
+		"Euphoria Loreen":   "euphoria",
+		"Gruppa Krovi Kino": "kino",
+	})
+
+	playlist := "#EXTM3U\n" +
+		"#EXTINF:184,Loreen - Euphoria\neuphoria.mp3\n" +
+		"#EXTINF:-1,Kino - Gruppa Krovi\nkino.mp3\n"
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = uploadRequest(t, "/import/playlist", "mix.m3u", playlist)
+
+	ImportPlaylistHandler(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response ImportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Format != "m3u" || response.Total != 2 || response.Resolved != 2 {
+		t.Errorf("Unexpected summary %q %d/%d", response.Format, response.Resolved, response.Total)
+	}
+	for i, id := range []string{"euphoria", "kino"} {
+		entry := response.Entries[i]
+		if entry.Position != i+1 || entry.Video == nil || entry.Video.ID != id {
+			t.Errorf("Expected entry %d to resolve to %s, got %+v", i+1, id, entry)
+		}
+	}
+	if response.Entries[0].Duration != 184 || response.Entries[0].Artists[0] != "Loreen" {
+		t.Errorf("Expected parsed metadata, got %+v", response.Entries[0])
+	}
+}
+
+// stubTwoLengths serves a 3:00 and a 5:00 upload, both titled after the
+// query, for every search.
+func stubTwoLengths(t *testing.T) {
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("search_query")
+		video := func(id, length string) string {
+			return `{"videoRenderer":{"videoId":"` + id + `","title":{"simpleText":"` + query + `"},"lengthText":{"simpleText":"` + length + `"}}}`
+		}
+		w.Write([]byte(`var ytInitialData = {"contents":[` + video("short", "3:00") + `,` + video("long", "5:00") + `]};`))
+	})
+}
+
+func TestImportPlaylistHandler_TrackDurationWins(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	stubTwoLengths(t)
+
+	playlist := "#EXTM3U\n#EXTINF:300,Loreen - Euphoria\neuphoria.mp3\n#EXTINF:-1,Kino - Gruppa Krovi\nkino.mp3\n"
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = uploadRequest(t, "/import/playlist?duration=180", "mix.m3u", playlist)
+
+	ImportPlaylistHandler(c)
+
+	var response ImportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	// The first entry's own length wins; the second has none, so the
+	// request's applies.
+	for i, id := range []string{"long", "short"} {
+		if entry := response.Entries[i]; entry.Video == nil || entry.Video.ID != id {
+			t.Errorf("Expected entry %d to resolve to %s, got %+v", i+1, id, entry.Video)
+		}
+	}
+}
+
+func TestImportPlaylistHandler_InvalidUploads(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		request func() *http.Request
+	}{
+		{"no file", func() *http.Request {
+			return httptest.NewRequest("POST", "/import/playlist", strings.NewReader(""))
+		}},
+		{"unknown format", func() *http.Request {
+			return uploadRequest(t, "/import/playlist", "notes.txt", "Loreen - Euphoria")
+		}},
+		{"broken XSPF", func() *http.Request {
+			return uploadRequest(t, "/import/playlist", "mix.xspf", "<playlist><trackList>")
+		}},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = tt.request()
+
+		ImportPlaylistHandler(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, tt.name, w.Code)
+		}
+	}
+}
+
+// countingReader counts the bytes read from it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func TestImportPlaylistHandler_OversizedUpload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// A file three times the limit, of which only the limit and the
+	// multipart overhead may be read.
+	body := &countingReader{r: io.MultiReader(
+		strings.NewReader("--boundary\r\nContent-Disposition: form-data; name=\"file\"; filename=\"mix.m3u\"\r\n\r\n"),
+		io.LimitReader(neverEndingReader{}, 3*maxImportSize),
+		strings.NewReader("\r\n--boundary--\r\n"),
+	)}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/import/playlist", body)
+	c.Request.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
+
+	ImportPlaylistHandler(c)
+
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "at most 10 MB") {
+		t.Errorf("Expected the upload to be rejected as too large, got %d: %s", w.Code, w.Body.String())
+	}
+	// One byte past the limit is read to detect that it was exceeded.
+	if limit := int64(maxImportSize + multipartOverhead + 1); body.n > limit {
+		t.Errorf("Expected at most %d bytes to be read, read %d", limit, body.n)
+	}
+}
+
+// neverEndingReader reads as an endless run of the letter a.
+type neverEndingReader struct{}
+
+func (neverEndingReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'a'
+	}
+	return len(p), nil
+}
+
+const testLibrary = `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0"><dict>
+	<key>Tracks</key><dict>
+		<key>1</key><dict><key>Name</key><string>Euphoria</string><key>Artist</key><string>Loreen</string></dict>
+		<key>2</key><dict><key>Name</key><string>Tattoo</string><key>Artist</key><string>Loreen</string></dict>
+		<key>3</key><dict><key>Name</key><string>Gruppa Krovi</string><key>Artist</key><string>Kino</string></dict>
+	</dict>
+	<key>Playlists</key><array>
+		<dict><key>Name</key><string>Loreen</string><key>Playlist Items</key><array>
+			<dict><key>Track ID</key><integer>2</integer></dict>
+			<dict><key>Track ID</key><integer>1</integer></dict>
+		</array></dict>
+		<dict><key>Name</key><string>Favorites</string><key>Playlist Items</key><array>
+			<dict><key>Track ID</key><integer>1</integer></dict>
+		</array></dict>
+	</array>
+</dict></plist>`
+
+func TestImportITunesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var searches []string
+	var mu sync.Mutex
+	videos := map[string]string{
+		"Euphoria Loreen":   "euphoria",
+		"Tattoo Loreen":     "tattoo",
+		"Gruppa Krovi Kino": "kino",
+	}
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("search_query")
+		mu.Lock()
+		searches = append(searches, query)
+		mu.Unlock()
+		w.Write([]byte(`var ytInitialData = {"contents":[` + renderer(videos[query], query, "") + `]};`))
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = uploadRequest(t, "/import/itunes", "Library.xml", testLibrary)
+
+	ImportITunesHandler(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response ITunesImportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Total != 3 || response.Resolved != 3 {
+		t.Errorf("Expected 3 of 3 tracks resolved, got %d of %d", response.Resolved, response.Total)
+	}
+	if len(searches) != 3 {
+		t.Errorf("Expected each track to be searched for once, got %v", searches)
+	}
+
+	if len(response.Playlists) != 2 {
+		t.Fatalf("Expected 2 playlists, got %+v", response.Playlists)
+	}
+	loreen := response.Playlists[0]
+	if loreen.Name != "Loreen" || len(loreen.Entries) != 2 ||
+		loreen.Entries[0].Video.ID != "tattoo" || loreen.Entries[1].Video.ID != "euphoria" || loreen.Entries[1].Position != 2 {
+		t.Errorf("Unexpected playlist %+v", loreen)
+	}
+	if favorites := response.Playlists[1]; len(favorites.Entries) != 1 || favorites.Entries[0].Video.ID != "euphoria" {
+		t.Errorf("Unexpected playlist %+v", favorites)
+	}
+	if len(response.Unlisted) != 1 || response.Unlisted[0].Video.ID != "kino" {
+		t.Errorf("Expected Gruppa Krovi to be unlisted, got %+v", response.Unlisted)
+	}
+}
+
+func TestImportITunesHandler_SelectedPlaylists(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	stubSearchResults(t, map[string]string{"Euphoria Loreen": "euphoria"})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = uploadRequest(t, "/import/itunes?playlist=Favorites", "Library.xml", testLibrary)
+
+	ImportITunesHandler(c)
+
+	var response ITunesImportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Total != 1 || len(response.Playlists) != 1 || response.Playlists[0].Name != "Favorites" {
+		t.Errorf("Expected only Favorites, got %+v", response)
+	}
+	if len(response.Unlisted) != 0 {
+		t.Errorf("Expected no unlisted tracks when playlists are selected, got %+v", response.Unlisted)
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = uploadRequest(t, "/import/itunes?playlist=Missing", "Library.xml", testLibrary)
+
+	ImportITunesHandler(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d for an unknown playlist, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestImportScrobblesHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	stubSearchResults(t, map[string]string{
+		"Euphoria Loreen": "euphoria",
+		"Tattoo Loreen":   "tattoo",
+		"Hello Adele":     "hello",
+	})
+
+	scrobbles := "Loreen,Heal,Tattoo,31 Jan 2024 12:00\n" +
+		"Loreen,Heal,Euphoria,31 Jan 2024 11:00\n" +
+		"LOREEN,Heal,Euphoria,30 Jan 2024 12:00\n" +
+		"Adele,25,Hello,29 Jan 2024 12:00\n" +
+		"Loreen,Heal,Euphoria,28 Jan 2024 12:00\n" +
+		"Loreen,Tattoo,Tattoo,27 Jan 2024 12:00\n"
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = uploadRequest(t, "/import/scrobbles?limit=2", "scrobbles.csv", scrobbles)
+
+	ImportScrobblesHandler(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response ScrobbleImportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Source != "lastfm" || response.Listens != 6 || response.Tracks != 3 {
+		t.Errorf("Unexpected summary %+v", response)
+	}
+	if len(response.Entries) != 2 {
+		t.Fatalf("Expected the top 2 tracks, got %+v", response.Entries)
+	}
+	if entry := response.Entries[0]; entry.Position != 1 || entry.Plays != 3 || entry.Video == nil || entry.Video.ID != "euphoria" {
+		t.Errorf("Unexpected first entry %+v", entry)
+	}
+	if entry := response.Entries[1]; entry.Plays != 2 || entry.Video == nil || entry.Video.ID != "tattoo" {
+		t.Errorf("Unexpected second entry %+v", entry)
+	}
+}
+
+func TestImportScrobblesHandler_ListenBrainz(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	stubSearchResults(t, map[string]string{"Euphoria Loreen": "euphoria"})
+
+	listens := `{"track_metadata":{"artist_name":"Loreen","track_name":"Euphoria"}}` + "\n" +
+		`{"track_metadata":{"artist_name":"Loreen","track_name":"Euphoria"}}` + "\n"
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = uploadRequest(t, "/import/scrobbles", "listens.jsonl", listens)
+
+	ImportScrobblesHandler(c)
+
+	var response ScrobbleImportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Source != "listenbrainz" || len(response.Entries) != 1 || response.Entries[0].Plays != 2 {
+		t.Errorf("Unexpected response %+v", response)
+	}
+}
+
+func TestImportScrobblesHandler_InvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		target  string
+		content string
+	}{
+		{"/import/scrobbles?limit=0", "Loreen,Heal,Euphoria,31 Jan 2024 12:00\n"},
+		{"/import/scrobbles?limit=many", "Loreen,Heal,Euphoria,31 Jan 2024 12:00\n"},
+		{"/import/scrobbles", `[{"track_metadata":`},
+		{"/import/scrobbles", "artist,album\nLoreen,Heal\n"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = uploadRequest(t, tt.target, "history", tt.content)
+
+		ImportScrobblesHandler(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s %q, got %d", http.StatusBadRequest, tt.target, tt.content, w.Code)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"youtube-music-video-api/internal/services"
+)
+
+type LookupResponse struct {
+	ISRC      string              `json:"isrc,omitempty"`
+	SpotifyID string              `json:"spotifyId,omitempty"`
+	Recording *services.Recording `json:"recording"`
+	SearchResponse
+}
+
+var (
+	metadataProvider services.MetadataProvider = services.NewMusicBrainzClient(serviceConfig.MusicBrainzBaseURL, serviceConfig.Timeout)
+	spotifyProvider  services.TrackProvider    = services.NewSpotifyClient(serviceConfig)
+)
+
+// LookupISRCHandler godoc
+// @Summary Find the music video for an ISRC
+// @Description Resolves an ISRC to a title and artists through MusicBrainz, then searches YouTube for it
+// @Tags lookup
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param isrc path string true "ISRC, with or without hyphens, e.g. USRC17607839"
+// @Param region query string false "ISO 3166-1 alpha-2 region code, e.g. SE"
+// @Param lang query string false "Language code, e.g. sv or en-US"
+// @Param availability query bool false "Report whether the video is playable in region"
+// @Param playable query bool false "Skip candidates that aren't playable in region"
+// @Param embeddable query bool false "Skip candidates with embedding disabled"
+// @Param exclude_age_restricted query bool false "Skip age-restricted candidates"
+// @Param duration query string false "Expected track length in seconds or mm:ss, overriding the recording's length"
+// @Param duration_tolerance query int false "Allowed duration difference in seconds"
+// @Param strict_duration query bool false "Skip candidates outside the duration tolerance instead of ranking them lower"
+// @Param min_confidence query number false "Return no video unless a candidate's confidence, between 0 and 1, reaches this"
+// @Param source query string false "youtube (default) searches youtube.com; music searches YouTube Music and also returns the song's audio track"
+// @Param include_candidates query bool false "List every ranked candidate with its similarity score"
+// @Param format query string false "Response format when the Accept header can't be set: json, xml, yaml or msgpack"
+// @Success 200 {object} LookupResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 406 {object} map[string]string
+// @Router /lookup/isrc/{isrc} [get]
+func LookupISRCHandler(c *gin.Context) {
+	isrc, ok := services.NormalizeISRC(c.Param("isrc"))
+	if !ok {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The ISRC must be 12 characters, e.g. USRC17607839."})
+		return
+	}
+
+	opts, err := parseSearchOptions(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	recording, err := metadataProvider.LookupISRC(isrc)
+	if errors.Is(err, services.ErrRecordingNotFound) {
+		respond(c, http.StatusNotFound, map[string]string{"error": "No recording was found for the ISRC."})
+		return
+	}
+	if err != nil {
+		log.Printf("Error looking up ISRC %s: %v", isrc, err)
+		respond(c, http.StatusInternalServerError, map[string]string{"error": "Failed to look up the ISRC"})
+		return
+	}
+
+	respondWithRecording(c, recording, opts, LookupResponse{ISRC: isrc, Recording: recording})
+}
+
+// LookupMusicBrainzHandler godoc
+// @Summary Find the music video for a MusicBrainz recording
+// @Description Fetches a MusicBrainz recording's title, artists and length, then searches YouTube for it
+// @Tags lookup
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param mbid path string true "MusicBrainz recording ID"
+// @Param region query string false "ISO 3166-1 alpha-2 region code, e.g. SE"
+// @Param lang query string false "Language code, e.g. sv or en-US"
+// @Param availability query bool false "Report whether the video is playable in region"
+// @Param playable query bool false "Skip candidates that aren't playable in region"
+// @Param embeddable query bool false "Skip candidates with embedding disabled"
+// @Param exclude_age_restricted query bool false "Skip age-restricted candidates"
+// @Param duration query string false "Expected track length in seconds or mm:ss, overriding the recording's length"
+// @Param duration_tolerance query int false "Allowed duration difference in seconds"
+// @Param strict_duration query bool false "Skip candidates outside the duration tolerance instead of ranking them lower"
+// @Param min_confidence query number false "Return no video unless a candidate's confidence, between 0 and 1, reaches this"
+// @Param source query string false "youtube (default) searches youtube.com; music searches YouTube Music and also returns the song's audio track"
+// @Param include_candidates query bool false "List every ranked candidate with its similarity score"
+// @Param format query string false "Response format when the Accept header can't be set: json, xml, yaml or msgpack"
+// @Success 200 {object} LookupResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 406 {object} map[string]string
+// @Router /lookup/musicbrainz/{mbid} [get]
+func LookupMusicBrainzHandler(c *gin.Context) {
+	mbid, ok := services.NormalizeMBID(c.Param("mbid"))
+	if !ok {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The MBID must be a MusicBrainz recording ID, e.g. b1a9c0e9-d987-4042-ae91-78d6a3267d69."})
+		return
+	}
+
+	opts, err := parseSearchOptions(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	recording, err := metadataProvider.LookupRecording(mbid)
+	if errors.Is(err, services.ErrRecordingNotFound) {
+		respond(c, http.StatusNotFound, map[string]string{"error": "No recording was found for the MBID."})
+		return
+	}
+	if err != nil {
+		log.Printf("Error looking up MusicBrainz recording %s: %v", mbid, err)
+		respond(c, http.StatusInternalServerError, map[string]string{"error": "Failed to look up the recording"})
+		return
+	}
+
+	respondWithRecording(c, recording, opts, LookupResponse{Recording: recording})
+}
+
+// LookupSpotifyHandler godoc
+// @Summary Find the music video for a Spotify track
+// @Description Resolves a Spotify track's title, artists and duration, then searches YouTube for it
+// @Tags lookup
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param uri query string true "spotify:track: URI, open.spotify.com track URL or track ID"
+// @Param region query string false "ISO 3166-1 alpha-2 region code, e.g. SE"
+// @Param lang query string false "Language code, e.g. sv or en-US"
+// @Param availability query bool false "Report whether the video is playable in region"
+// @Param playable query bool false "Skip candidates that aren't playable in region"
+// @Param embeddable query bool false "Skip candidates with embedding disabled"
+// @Param exclude_age_restricted query bool false "Skip age-restricted candidates"
+// @Param duration query string false "Expected track length in seconds or mm:ss, overriding the recording's length"
+// @Param duration_tolerance query int false "Allowed duration difference in seconds"
+// @Param strict_duration query bool false "Skip candidates outside the duration tolerance instead of ranking them lower"
+// @Param min_confidence query number false "Return no video unless a candidate's confidence, between 0 and 1, reaches this"
+// @Param source query string false "youtube (default) searches youtube.com; music searches YouTube Music and also returns the song's audio track"
+// @Param include_candidates query bool false "List every ranked candidate with its similarity score"
+// @Param format query string false "Response format when the Accept header can't be set: json, xml, yaml or msgpack"
+// @Success 200 {object} LookupResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 406 {object} map[string]string
+// @Router /lookup/spotify [get]
+func LookupSpotifyHandler(c *gin.Context) {
+	id, ok := services.ParseSpotifyTrackID(c.Query("uri"))
+	if !ok {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The uri must be a spotify:track: URI or an open.spotify.com track link."})
+		return
+	}
+
+	opts, err := parseSearchOptions(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	recording, err := spotifyProvider.LookupTrack(id)
+	if errors.Is(err, services.ErrRecordingNotFound) {
+		respond(c, http.StatusNotFound, map[string]string{"error": "No track was found for the Spotify ID."})
+		return
+	}
+	if err != nil {
+		log.Printf("Error looking up Spotify track %s: %v", id, err)
+		respond(c, http.StatusInternalServerError, map[string]string{"error": "Failed to look up the Spotify track"})
+		return
+	}
+
+	respondWithRecording(c, recording, opts, LookupResponse{SpotifyID: id, Recording: recording})
+}
+
+// respondWithRecording searches YouTube for recording and completes
+// response with the result. The recording's length is used for duration
+// matching unless the request sets its own duration.
+func respondWithRecording(c *gin.Context, recording *services.Recording, opts services.SearchOptions, response LookupResponse) {
+	if opts.Duration == 0 && recording.Duration > 0 {
+		opts.Duration = time.Duration(recording.Duration) * time.Second
+	}
+
+	resolution, err := youtubeService.Resolve(recording.Title, recording.Artists, opts)
+	if err != nil {
+		log.Printf("Error searching YouTube for title '%s' with artists %v: %v", recording.Title, recording.Artists, err)
+		respond(c, http.StatusInternalServerError, map[string]string{"error": "Failed to search YouTube"})
+		return
+	}
+
+	response.SearchResponse = newSearchResponse(c, recording.Title, recording.Artists, opts, resolution)
+	respond(c, http.StatusOK, response)
+}
+
+// lookupExternalID resolves an ISRC, MusicBrainz recording ID or Spotify
+// track URI to a recording. It returns services.ErrRecordingNotFound when id
+// is none of these.
+func lookupExternalID(id string) (*services.Recording, error) {
+	if isrc, ok := services.NormalizeISRC(id); ok {
+		return metadataProvider.LookupISRC(isrc)
+	}
+	if mbid, ok := services.NormalizeMBID(id); ok {
+		return metadataProvider.LookupRecording(mbid)
+	}
+	if spotifyID, ok := services.ParseSpotifyTrackID(id); ok {
+		return spotifyProvider.LookupTrack(spotifyID)
+	}
+	return nil, services.ErrRecordingNotFound
+}
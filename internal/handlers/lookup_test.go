@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"youtube-music-video-api/internal/services"
+)
+
+// stubMetadataProvider serves recordings from memory.
+type stubMetadataProvider struct {
+	isrcs      map[string]*services.Recording
+	recordings map[string]*services.Recording
+}
+
+func (p *stubMetadataProvider) LookupISRC(isrc string) (*services.Recording, error) {
+	if recording, ok := p.isrcs[isrc]; ok {
+		return recording, nil
+	}
+	return nil, services.ErrRecordingNotFound
+}
+
+func (p *stubMetadataProvider) LookupRecording(mbid string) (*services.Recording, error) {
+	if recording, ok := p.recordings[mbid]; ok {
+		return recording, nil
+	}
+	return nil, services.ErrRecordingNotFound
+}
+
+// stubTrackProvider serves Spotify tracks from memory.
+type stubTrackProvider map[string]*services.Recording
+
+func (p stubTrackProvider) LookupTrack(id string) (*services.Recording, error) {
+	if recording, ok := p[id]; ok {
+		return recording, nil
+	}
+	return nil, services.ErrRecordingNotFound
+}
+
+func useStubMetadata(t *testing.T, provider services.MetadataProvider) {
+	t.Helper()
+
+	original := metadataProvider
+	metadataProvider = provider
+	t.Cleanup(func() { metadataProvider = original })
+}
+
+func TestLookupISRCHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	useStubMetadata(t, &stubMetadataProvider{isrcs: map[string]*services.Recording{
+		"USRC17607839": {Title: "Never Gonna Give You Up", Artists: []string{"Rick Astley"}},
+	}})
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("search_query") != "Never Gonna Give You Up Rick Astley" {
+			t.Errorf("Unexpected search query %q", r.URL.Query().Get("search_query"))
+		}
+		w.Write([]byte(`var ytInitialData = {"contents":[` +
+			`{"videoRenderer":{"videoId":"dQw4w9WgXcQ","title":{"simpleText":"Rick Astley - Never Gonna Give You Up"},"ownerText":{"runs":[{"text":"Rick Astley"}]}}}]};`))
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/lookup/isrc/us-rc1-76-07839", nil)
+	c.Params = gin.Params{{Key: "isrc", Value: "us-rc1-76-07839"}}
+
+	LookupISRCHandler(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response LookupResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.ISRC != "USRC17607839" {
+		t.Errorf("Expected normalized ISRC, got %q", response.ISRC)
+	}
+	if response.Recording == nil || response.Recording.Title != "Never Gonna Give You Up" {
+		t.Errorf("Unexpected recording %+v", response.Recording)
+	}
+	if response.Input.Title != "Never Gonna Give You Up" {
+		t.Errorf("Expected input title from the recording, got %q", response.Input.Title)
+	}
+	if response.Video == nil || response.Video.ID != "dQw4w9WgXcQ" {
+		t.Errorf("Unexpected video %+v", response.Video)
+	}
+}
+
+func TestLookupISRCHandler_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	useStubMetadata(t, &stubMetadataProvider{})
+
+	tests := []struct {
+		isrc   string
+		status int
+	}{
+		{"not-an-isrc", http.StatusBadRequest},
+		{"USRC1760783", http.StatusBadRequest},
+		{"USRC17607839", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/lookup/isrc/"+tt.isrc, nil)
+		c.Params = gin.Params{{Key: "isrc", Value: tt.isrc}}
+
+		LookupISRCHandler(c)
+
+		if w.Code != tt.status {
+			t.Errorf("Expected status %d for %q, got %d", tt.status, tt.isrc, w.Code)
+		}
+	}
+}
+
+func TestLookupMusicBrainzHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const mbid = "b1a9c0e9-d987-4042-ae91-78d6a3267d69"
+	useStubMetadata(t, &stubMetadataProvider{recordings: map[string]*services.Recording{
+		mbid: {ID: mbid, Title: "Never Gonna Give You Up", Artists: []string{"Rick Astley"}, Duration: 213},
+	}})
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`var ytInitialData = {"contents":[` +
+			`{"videoRenderer":{"videoId":"extended","title":{"simpleText":"Rick Astley - Never Gonna Give You Up (Extended Mix)"},"ownerText":{"runs":[{"text":"Rick Astley"}]},"lengthText":{"simpleText":"6:45"}}},` +
+			`{"videoRenderer":{"videoId":"dQw4w9WgXcQ","title":{"simpleText":"Rick Astley - Never Gonna Give You Up"},"ownerText":{"runs":[{"text":"Rick Astley"}]},"lengthText":{"simpleText":"3:33"}}}]};`))
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/lookup/musicbrainz/"+strings.ToUpper(mbid), nil)
+	c.Params = gin.Params{{Key: "mbid", Value: strings.ToUpper(mbid)}}
+
+	LookupMusicBrainzHandler(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response LookupResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Recording == nil || response.Recording.ID != mbid {
+		t.Errorf("Unexpected recording %+v", response.Recording)
+	}
+	if response.Input.Duration != 213 {
+		t.Errorf("Expected the recording length to be used as duration, got %d", response.Input.Duration)
+	}
+	if response.Video == nil || response.Video.ID != "dQw4w9WgXcQ" {
+		t.Errorf("Expected the video matching the recording length, got %+v", response.Video)
+	}
+}
+
+func TestLookupMusicBrainzHandler_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	useStubMetadata(t, &stubMetadataProvider{})
+
+	tests := []struct {
+		mbid   string
+		status int
+	}{
+		{"not-an-mbid", http.StatusBadRequest},
+		{"b1a9c0e9-d987-4042-ae91-78d6a3267d6", http.StatusBadRequest},
+		{"b1a9c0e9-d987-4042-ae91-78d6a3267d69", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/lookup/musicbrainz/"+tt.mbid, nil)
+		c.Params = gin.Params{{Key: "mbid", Value: tt.mbid}}
+
+		LookupMusicBrainzHandler(c)
+
+		if w.Code != tt.status {
+			t.Errorf("Expected status %d for %q, got %d", tt.status, tt.mbid, w.Code)
+		}
+	}
+}
+
+func TestLookupSpotifyHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	original := spotifyProvider
+	spotifyProvider = stubTrackProvider{
+		"4uLU6hMCjMI75M1A2tKUQC": {ID: "4uLU6hMCjMI75M1A2tKUQC", Title: "Never Gonna Give You Up", Artists: []string{"Rick Astley"}, Duration: 214},
+	}
+	t.Cleanup(func() { spotifyProvider = original })
+
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`var ytInitialData = {"contents":[` +
+			`{"videoRenderer":{"videoId":"dQw4w9WgXcQ","title":{"simpleText":"Rick Astley - Never Gonna Give You Up"},"ownerText":{"runs":[{"text":"Rick Astley"}]},"lengthText":{"simpleText":"3:33"}}}]};`))
+	})
+
+	tests := []struct {
+		uri    string
+		status int
+	}{
+		{"spotify:track:4uLU6hMCjMI75M1A2tKUQC", http.StatusOK},
+		{"https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC?si=abc", http.StatusOK},
+		{"spotify:track:0000000000000000000000", http.StatusNotFound},
+		{"spotify:album:4uLU6hMCjMI75M1A2tKUQC", http.StatusBadRequest},
+		{"", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			URL: &url.URL{RawQuery: url.Values{"uri": {tt.uri}}.Encode()},
+		}
+
+		LookupSpotifyHandler(c)
+
+		if w.Code != tt.status {
+			t.Errorf("Expected status %d for %q, got %d", tt.status, tt.uri, w.Code)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var response LookupResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if response.SpotifyID != "4uLU6hMCjMI75M1A2tKUQC" {
+			t.Errorf("Expected Spotify ID, got %q", response.SpotifyID)
+		}
+		if response.Input.Duration != 214 {
+			t.Errorf("Expected the track duration to be used, got %d", response.Input.Duration)
+		}
+		if response.Video == nil || response.Video.ID != "dQw4w9WgXcQ" {
+			t.Errorf("Unexpected video %+v", response.Video)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const defaultEmbedWidth = 480
+
+// oembedThumbnails are the fixed-size thumbnails YouTube serves for every
+// video, largest first.
+var oembedThumbnails = []struct {
+	name          string
+	width, height int
+}{
+	{"hqdefault", 480, 360},
+	{"mqdefault", 320, 180},
+	{"default", 120, 90},
+}
+
+// OEmbedResponse is an oEmbed 1.0 video response.
+type OEmbedResponse struct {
+	Type            string `json:"type"`
+	Version         string `json:"version"`
+	Title           string `json:"title,omitempty"`
+	AuthorName      string `json:"author_name,omitempty"`
+	AuthorURL       string `json:"author_url,omitempty"`
+	ProviderName    string `json:"provider_name"`
+	ProviderURL     string `json:"provider_url"`
+	ThumbnailURL    string `json:"thumbnail_url"`
+	ThumbnailWidth  int    `json:"thumbnail_width"`
+	ThumbnailHeight int    `json:"thumbnail_height"`
+	HTML            string `json:"html"`
+	Width           int    `json:"width"`
+	Height          int    `json:"height"`
+}
+
+// XMLRoot names the root element of the XML response, which oEmbed requires
+// to be oembed.
+func (OEmbedResponse) XMLRoot() string {
+	return "oembed"
+}
+
+// XMLContentType is text/xml, the media type oEmbed requires for XML
+// responses.
+func (OEmbedResponse) XMLContentType() string {
+	return binding.MIMEXML2
+}
+
+// OEmbedHandler godoc
+// @Summary Get an oEmbed embed for a song
+// @Description Resolves a song to an embeddable music video and returns an oEmbed 1.0 video response with the YouTube iframe. The song is given as title and artists, or as url holding the text an editor pasted, such as "Loreen - Euphoria".
+// @Tags oembed
+// @Produce json,xml
+// @Param url query string false "Song as \"Artist - Title\", used when title is empty"
+// @Param title query string false "Title to search for"
+// @Param artists query string false "Artist name or comma-separated list of artists"
+// @Param maxwidth query int false "Maximum embed and thumbnail width in pixels"
+// @Param maxheight query int false "Maximum embed and thumbnail height in pixels"
+// @Param format query string false "json (default) or xml"
+// @Param region query string false "ISO 3166-1 alpha-2 region code, e.g. SE"
+// @Param lang query string false "Language code, e.g. sv or en-US"
+// @Param duration query string false "Expected track length in seconds or mm:ss"
+// @Param min_confidence query number false "Return no video unless a candidate's confidence, between 0 and 1, reaches this"
+// @Success 200 {object} OEmbedResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 501 {object} map[string]string
+// @Router /oembed [get]
+func OEmbedHandler(c *gin.Context) {
+	// oEmbed only defines JSON and XML and asks for 501 Not Implemented
+	// when another format is requested.
+	format := strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", "json")))
+	if format != "json" && format != "xml" {
+		c.JSON(http.StatusNotImplemented, map[string]string{"error": "The format must be json or xml."})
+		return
+	}
+	c.Set(responseFormatKey, format)
+
+	title := strings.TrimSpace(c.Query("title"))
+	artists := splitArtists(c.Query("artists"))
+	if title == "" {
+		title, artists = parsePastedSong(c.Query("url"))
+	}
+	if title == "" {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The title or url can't be empty."})
+		return
+	}
+
+	maxWidth, err := parseDimension(c, "maxwidth")
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	maxHeight, err := parseDimension(c, "maxheight")
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	opts, err := parseSearchOptions(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	// A video that can't be embedded is of no use to an oEmbed consumer.
+	opts.RequireEmbeddable = true
+
+	resolution, err := youtubeService.Resolve(title, artists, opts)
+	if err != nil {
+		log.Printf("Error searching YouTube for title '%s' with artists %v: %v", title, artists, err)
+		respond(c, http.StatusInternalServerError, map[string]string{"error": "Failed to search YouTube"})
+		return
+	}
+	if resolution.Video == nil {
+		respond(c, http.StatusNotFound, map[string]string{"error": resolution.Reason})
+		return
+	}
+
+	video := resolution.Video
+	response := OEmbedResponse{
+		Type:         "video",
+		Version:      "1.0",
+		Title:        video.Title,
+		AuthorName:   video.Channel,
+		ProviderName: "YouTube",
+		ProviderURL:  "https://www.youtube.com/",
+	}
+	response.ThumbnailURL, response.ThumbnailWidth, response.ThumbnailHeight = oembedThumbnail(c, video.ID, maxWidth, maxHeight)
+
+	// The watch page names the channel's URL, and the title and author when
+	// the search result didn't.
+	if details, err := youtubeService.GetVideoDetails(video.ID); err != nil {
+		log.Printf("Failed to fetch details for video %s: %v", video.ID, err)
+	} else {
+		if response.Title == "" {
+			response.Title = details.Title
+		}
+		if response.AuthorName == "" {
+			response.AuthorName = details.Channel
+		}
+		if details.ChannelID != "" {
+			response.AuthorURL = "https://www.youtube.com/channel/" + details.ChannelID
+		}
+	}
+
+	response.Width, response.Height = embedSize(maxWidth, maxHeight)
+	response.HTML = fmt.Sprintf(
+		`<iframe width="%d" height="%d" src="https://www.youtube.com/embed/%s?feature=oembed" title="%s" frameborder="0" allow="accelerometer; autoplay; clipboard-write; encrypted-media; gyroscope; picture-in-picture; web-share" referrerpolicy="strict-origin-when-cross-origin" allowfullscreen></iframe>`,
+		response.Width, response.Height, video.ID, html.EscapeString(response.Title),
+	)
+
+	respond(c, http.StatusOK, response)
+}
+
+// parsePastedSong splits text like "Loreen - Euphoria" into its title and
+// artists. Text without an artist is taken as the title.
+func parsePastedSong(text string) (string, []string) {
+	text = strings.TrimSpace(text)
+	artist, title, found := strings.Cut(text, " - ")
+	if !found {
+		return text, nil
+	}
+	return strings.TrimSpace(title), splitArtists(artist)
+}
+
+// splitArtists splits a comma-separated list of artists, dropping empty
+// names.
+func splitArtists(value string) []string {
+	var artists []string
+	for _, artist := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(artist); trimmed != "" {
+			artists = append(artists, trimmed)
+		}
+	}
+	return artists
+}
+
+// parseDimension reads an optional positive pixel size from the query.
+func parseDimension(c *gin.Context, name string) (int, error) {
+	value := strings.TrimSpace(c.Query(name))
+	if value == "" {
+		return 0, nil
+	}
+	pixels, err := strconv.Atoi(value)
+	if err != nil || pixels <= 0 {
+		return 0, fmt.Errorf("The %s must be a positive number of pixels.", name)
+	}
+	return pixels, nil
+}
+
+// oembedThumbnail returns the URL and size of the largest of YouTube's
+// thumbnails that fits maxWidth and maxHeight, where 0 means unbounded. When
+// none fits, it is this server's /thumbnails endpoint shrinking mqdefault.
+func oembedThumbnail(c *gin.Context, videoID string, maxWidth, maxHeight int) (string, int, int) {
+	for _, thumbnail := range oembedThumbnails {
+		if (maxWidth == 0 || thumbnail.width <= maxWidth) && (maxHeight == 0 || thumbnail.height <= maxHeight) {
+			return "https://i.ytimg.com/vi/" + videoID + "/" + thumbnail.name + ".jpg", thumbnail.width, thumbnail.height
+		}
+	}
+
+	// Shrink 320x180 to fit both bounds, as the endpoint does.
+	scale := 1.0
+	if maxWidth > 0 {
+		scale = min(scale, float64(maxWidth)/320)
+	}
+	if maxHeight > 0 {
+		scale = min(scale, float64(maxHeight)/180)
+	}
+	width := max(1, int(320*scale+0.5))
+	height := max(1, int(180*scale+0.5))
+
+	scheme := "http"
+	if c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s/thumbnails/%s?quality=mq&width=%d&height=%d", scheme, c.Request.Host, videoID, width, height), width, height
+}
+
+// embedSize returns the largest 16:9 player size that fits maxWidth and
+// maxHeight, where 0 means unbounded, defaulting to defaultEmbedWidth wide.
+func embedSize(maxWidth, maxHeight int) (int, int) {
+	width := defaultEmbedWidth
+	if maxWidth > 0 {
+		width = maxWidth
+	}
+	height := width * 9 / 16
+
+	if maxHeight > 0 && height > maxHeight {
+		height = maxHeight
+		width = height * 16 / 9
+	}
+	return width, height
+}
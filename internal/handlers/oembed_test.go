@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// useStubEmbeds serves a search for Euphoria by Loreen whose first result
+// can't be embedded, and only that result for any other search. It returns
+// the number of times the embeddable video's watch page was fetched.
+func useStubEmbeds(t *testing.T) *atomic.Int32 {
+	t.Helper()
+
+	var watchPageFetches atomic.Int32
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("v") {
+		case "":
+			if r.URL.Query().Get("search_query") != "Euphoria Loreen" {
+				w.Write([]byte(`var ytInitialData = {"contents":[` + renderer("noEmbed0000", "Loreen - Euphoria", "Loreen") + `]};`))
+				return
+			}
+			w.Write([]byte(`var ytInitialData = {"contents":[` +
+				renderer("noEmbed0000", "Loreen - Euphoria", "Loreen") + `,` +
+				renderer("euphoria123", "Loreen - Euphoria (Official Video)", "Loreen") + `]};`))
+		case "noEmbed0000":
+			w.Write([]byte(`var ytInitialPlayerResponse = {"playabilityStatus":{"status":"OK","playableInEmbed":false}};`))
+		default:
+			watchPageFetches.Add(1)
+			w.Write([]byte(`var ytInitialPlayerResponse = {"playabilityStatus":{"status":"OK","playableInEmbed":true},` +
+				`"videoDetails":{"videoId":"euphoria123","title":"Loreen - Euphoria (Official Video)","author":"Loreen","channelId":"UCloreen"}};`))
+		}
+	})
+	return &watchPageFetches
+}
+
+func oembedRequest(query url.Values) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/oembed?"+query.Encode(), nil)
+
+	OEmbedHandler(c)
+	return w
+}
+
+func TestOEmbedHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	watchPageFetches := useStubEmbeds(t)
+
+	w := oembedRequest(url.Values{"url": {"Loreen - Euphoria"}, "maxwidth": {"640"}, "maxheight": {"300"}})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var response OEmbedResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Type != "video" || response.Version != "1.0" {
+		t.Errorf("Expected an oEmbed 1.0 video, got type %q version %q", response.Type, response.Version)
+	}
+	if response.Title != "Loreen - Euphoria (Official Video)" || response.AuthorName != "Loreen" {
+		t.Errorf("Expected the embeddable video's title and author, got %q by %q", response.Title, response.AuthorName)
+	}
+	if response.AuthorURL != "https://www.youtube.com/channel/UCloreen" {
+		t.Errorf("Expected the channel URL, got %q", response.AuthorURL)
+	}
+	if response.ThumbnailURL != "https://i.ytimg.com/vi/euphoria123/mqdefault.jpg" {
+		t.Errorf("Expected the video's 320x180 thumbnail, which fits 640x300, got %q", response.ThumbnailURL)
+	}
+	if response.ThumbnailWidth != 320 || response.ThumbnailHeight != 180 {
+		t.Errorf("Expected a 320x180 thumbnail, got %dx%d", response.ThumbnailWidth, response.ThumbnailHeight)
+	}
+	if response.Width != 533 || response.Height != 300 {
+		t.Errorf("Expected a 533x300 embed, got %dx%d", response.Width, response.Height)
+	}
+	if !strings.Contains(response.HTML, `src="https://www.youtube.com/embed/euphoria123?feature=oembed"`) ||
+		!strings.Contains(response.HTML, `width="533" height="300"`) {
+		t.Errorf("Expected an iframe embedding euphoria123, got %s", response.HTML)
+	}
+	if fetches := watchPageFetches.Load(); fetches != 1 {
+		t.Errorf("Expected the watch page to be fetched once, got %d", fetches)
+	}
+}
+
+func TestOEmbedHandler_XML(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	useStubEmbeds(t)
+
+	w := oembedRequest(url.Values{"title": {"Euphoria"}, "artists": {"Loreen"}, "format": {"xml"}})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/xml") {
+		t.Errorf("Expected the text/xml content type oEmbed requires, got %s", contentType)
+	}
+
+	var response struct {
+		XMLName xml.Name
+		Type    string `xml:"type"`
+		Width   int    `xml:"width"`
+		Height  int    `xml:"height"`
+	}
+	if err := xml.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.XMLName.Local != "oembed" {
+		t.Errorf("Expected root element oembed, got %s", response.XMLName.Local)
+	}
+	if response.Type != "video" || response.Width != 480 || response.Height != 270 {
+		t.Errorf("Expected a 480x270 video, got %+v", response)
+	}
+}
+
+func TestOEmbedHandler_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	useStubEmbeds(t)
+
+	tests := []struct {
+		name   string
+		query  url.Values
+		status int
+	}{
+		{"no song", url.Values{}, http.StatusBadRequest},
+		{"invalid maxwidth", url.Values{"url": {"Loreen - Euphoria"}, "maxwidth": {"wide"}}, http.StatusBadRequest},
+		{"unsupported format", url.Values{"url": {"Loreen - Euphoria"}, "format": {"yaml"}}, http.StatusNotImplemented},
+		{"no match", url.Values{"url": {"Nobody - Nothing"}}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := oembedRequest(tt.query)
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestOEmbedThumbnail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		maxWidth, maxHeight int
+		url                 string
+		width, height       int
+	}{
+		{0, 0, "https://i.ytimg.com/vi/euphoria123/hqdefault.jpg", 480, 360},
+		{480, 0, "https://i.ytimg.com/vi/euphoria123/hqdefault.jpg", 480, 360},
+		{400, 0, "https://i.ytimg.com/vi/euphoria123/mqdefault.jpg", 320, 180},
+		{0, 100, "https://i.ytimg.com/vi/euphoria123/default.jpg", 120, 90},
+		{200, 150, "https://i.ytimg.com/vi/euphoria123/default.jpg", 120, 90},
+		{100, 0, "http://example.com/thumbnails/euphoria123?quality=mq&width=100&height=56", 100, 56},
+		{100, 45, "http://example.com/thumbnails/euphoria123?quality=mq&width=80&height=45", 80, 45},
+	}
+
+	for _, tt := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "http://example.com/oembed", nil)
+
+		thumbnailURL, width, height := oembedThumbnail(c, "euphoria123", tt.maxWidth, tt.maxHeight)
+		if thumbnailURL != tt.url || width != tt.width || height != tt.height {
+			t.Errorf("oembedThumbnail(%d, %d) = %q %dx%d, expected %q %dx%d",
+				tt.maxWidth, tt.maxHeight, thumbnailURL, width, height, tt.url, tt.width, tt.height)
+		}
+	}
+}
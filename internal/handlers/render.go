@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gin-gonic/gin/render"
+	"gopkg.in/yaml.v3"
+)
+
+// responseFormatKey is the context key under which NegotiateResponseFormat
+// stores the chosen format.
+const responseFormatKey = "responseFormat"
+
+// offeredMIMETypes lists the accepted media types in order of preference,
+// so that a wildcard Accept header gets JSON.
+var offeredMIMETypes = []string{
+	binding.MIMEJSON,
+	binding.MIMEXML, binding.MIMEXML2,
+	binding.MIMEYAML2, binding.MIMEYAML,
+	binding.MIMEMSGPACK2, binding.MIMEMSGPACK,
+}
+
+var formatsByMIMEType = map[string]string{
+	binding.MIMEJSON:     "json",
+	binding.MIMEXML:      "xml",
+	binding.MIMEXML2:     "xml",
+	binding.MIMEYAML:     "yaml",
+	binding.MIMEYAML2:    "yaml",
+	binding.MIMEMSGPACK:  "msgpack",
+	binding.MIMEMSGPACK2: "msgpack",
+}
+
+var formatAliases = map[string]string{
+	"json":        "json",
+	"xml":         "xml",
+	"yaml":        "yaml",
+	"yml":         "yaml",
+	"msgpack":     "msgpack",
+	"messagepack": "msgpack",
+}
+
+var xmlNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9._-]*$`)
+
+// NegotiateResponseFormat chooses how the response is encoded from the
+// format query parameter, or else the Accept header, and rejects the request
+// with 406 Not Acceptable before it is handled when neither can be met.
+func NegotiateResponseFormat(c *gin.Context) {
+	format, ok := responseFormat(c)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusNotAcceptable, map[string]string{"error": "The response format must be JSON, XML, YAML or MessagePack."})
+		return
+	}
+
+	c.Set(responseFormatKey, format)
+	c.Next()
+}
+
+// responseFormat returns json, xml, yaml or msgpack, or false when the
+// request only accepts other formats.
+func responseFormat(c *gin.Context) (string, bool) {
+	if value := strings.TrimSpace(c.Query("format")); value != "" {
+		format, ok := formatAliases[strings.ToLower(value)]
+		return format, ok
+	}
+
+	format, ok := formatsByMIMEType[negotiateMIMEType(c.GetHeader("Accept"))]
+	return format, ok
+}
+
+// negotiateMIMEType returns the offered media type with the highest quality
+// in accept, preferring the earliest in offeredMIMETypes on ties, or "" when
+// none is acceptable. A missing Accept header accepts JSON.
+//
+// Browsers list application/xml below text/html but above */*, so when
+// text/html or application/xhtml+xml is among the most preferred types the
+// request is treated as a page load and gets JSON if it accepts it at all.
+func negotiateMIMEType(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return binding.MIMEJSON
+	}
+
+	ranges := parseAccept(accept)
+
+	best, bestQuality := "", 0.0
+	for _, offered := range offeredMIMETypes {
+		if quality := acceptQuality(ranges, offered); quality > bestQuality {
+			best, bestQuality = offered, quality
+		}
+	}
+	if best == "" {
+		return ""
+	}
+
+	page := max(acceptQuality(ranges, "text/html"), acceptQuality(ranges, "application/xhtml+xml"))
+	if page >= bestQuality && acceptQuality(ranges, binding.MIMEJSON) > 0 {
+		return binding.MIMEJSON
+	}
+	return best
+}
+
+// acceptRange is one media range of an Accept header.
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+		if mediaType == "*" {
+			mediaType = "*/*"
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			name, value, _ := strings.Cut(param, "=")
+			if strings.TrimSpace(strings.ToLower(name)) != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q >= 0 && q <= 1 {
+				quality = q
+			}
+		}
+
+		ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+	}
+	return ranges
+}
+
+// acceptQuality returns the quality of mediaType under the most specific
+// matching range, or 0 when no range matches.
+func acceptQuality(ranges []acceptRange, mediaType string) float64 {
+	mainType, _, _ := strings.Cut(mediaType, "/")
+
+	quality, specificity := 0.0, -1
+	for _, r := range ranges {
+		var s int
+		switch r.mediaType {
+		case mediaType:
+			s = 2
+		case mainType + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity {
+			quality, specificity = r.quality, s
+		}
+	}
+	return quality
+}
+
+// respond writes obj in the format chosen by NegotiateResponseFormat. On
+// routes without the middleware the request is negotiated here, falling back
+// to JSON.
+func respond(c *gin.Context, code int, obj any) {
+	format := c.GetString(responseFormatKey)
+	if format == "" {
+		format, _ = responseFormat(c)
+	}
+
+	switch format {
+	case "xml":
+		c.Render(code, xmlRender{obj})
+	case "yaml":
+		c.Render(code, yamlRender{obj})
+	case "msgpack":
+		c.Render(code, render.MsgPack{Data: obj})
+	default:
+		c.JSON(code, obj)
+	}
+}
+
+// xmlRender writes the JSON encoding of Data as XML: the root element is
+// named by Data's XMLRoot method or else after its type, each object key
+// becomes an element, and each array element becomes an item element inside
+// its key's element. Data's XMLContentType method, if any, overrides the
+// application/xml media type.
+type xmlRender struct {
+	Data any
+}
+
+func (r xmlRender) WriteContentType(w http.ResponseWriter) {
+	mediaType := binding.MIMEXML
+	if typed, ok := r.Data.(interface{ XMLContentType() string }); ok {
+		mediaType = typed.XMLContentType()
+	}
+	w.Header().Set("Content-Type", mediaType+"; charset=utf-8")
+}
+
+func (r xmlRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+
+	encoded, err := json.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+
+	root := "response"
+	if named, ok := r.Data.(interface{ XMLRoot() string }); ok {
+		root = named.XMLRoot()
+	} else if t := reflect.TypeOf(r.Data); t != nil {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		if t.Name() != "" {
+			root = t.Name()
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&b)
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	if err := writeXMLValue(decoder, encoder, root); err != nil {
+		return err
+	}
+	if err := encoder.Flush(); err != nil {
+		return err
+	}
+
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+// writeXMLValue reads the next JSON value from decoder and writes it as the
+// element name.
+func writeXMLValue(decoder *json.Decoder, encoder *xml.Encoder, name string) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if !xmlNamePattern.MatchString(name) {
+		start = xml.StartElement{
+			Name: xml.Name{Local: "entry"},
+			Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: name}},
+		}
+	}
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+
+	switch token := token.(type) {
+	case json.Delim:
+		for decoder.More() {
+			child := "item"
+			if token == '{' {
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				child = key.(string)
+			}
+			if err := writeXMLValue(decoder, encoder, child); err != nil {
+				return err
+			}
+		}
+		// Consume the closing delimiter.
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		if err := encoder.EncodeToken(xml.CharData(fmt.Sprint(token))); err != nil {
+			return err
+		}
+	}
+
+	return encoder.EncodeToken(start.End())
+}
+
+// yamlRender writes the JSON encoding of Data as YAML, so keys keep their
+// JSON names and order.
+type yamlRender struct {
+	Data any
+}
+
+func (r yamlRender) WriteContentType(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+}
+
+func (r yamlRender) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+
+	encoded, err := json.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+
+	// JSON is YAML, so parsing it yields a document with the same
+	// structure; only the quoted, flow style needs to be dropped where it
+	// isn't needed.
+	var document yaml.Node
+	if err := yaml.Unmarshal(encoded, &document); err != nil {
+		return err
+	}
+	clearYAMLStyle(&document)
+
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+// clearYAMLStyle drops the JSON style from node and its children, except
+// that strings yaml.Marshal would quote stay quoted. Those include "Yes",
+// "no" and "1:00", which YAML 1.1 parsers read as booleans and numbers.
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" && needsYAMLQuotes(node.Value) {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
+
+func needsYAMLQuotes(value string) bool {
+	encoded, err := yaml.Marshal(value)
+	return err == nil && len(encoded) > 0 && (encoded[0] == '"' || encoded[0] == '\'')
+}
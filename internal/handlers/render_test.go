@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+	"gopkg.in/yaml.v3"
+)
+
+func negotiatedSearch(t *testing.T, target, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	stubSearchResults(t, map[string]string{"Euphoria Loreen": "euphoria123"})
+
+	r := gin.New()
+	r.GET("/search", NegotiateResponseFormat, SearchHandler)
+
+	req := httptest.NewRequest("GET", target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestNegotiateResponseFormat_XML(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := negotiatedSearch(t, "/search?title=Euphoria&artists=Loreen", "application/xml")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/xml") {
+		t.Errorf("Expected an XML content type, got %s", contentType)
+	}
+
+	var response struct {
+		XMLName xml.Name
+		Input   struct {
+			Artists []string `xml:"artists>item"`
+		} `xml:"input"`
+		Video struct {
+			ID string `xml:"id"`
+		} `xml:"video"`
+	}
+	if err := xml.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v\n%s", err, w.Body.String())
+	}
+
+	if response.XMLName.Local != "SearchResponse" {
+		t.Errorf("Expected root element SearchResponse, got %s", response.XMLName.Local)
+	}
+	if len(response.Input.Artists) != 1 || response.Input.Artists[0] != "Loreen" {
+		t.Errorf("Expected artists [Loreen], got %v", response.Input.Artists)
+	}
+	if response.Video.ID != "euphoria123" {
+		t.Errorf("Expected video ID euphoria123, got %q", response.Video.ID)
+	}
+}
+
+func TestNegotiateResponseFormat_YAML(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := negotiatedSearch(t, "/search?title=Euphoria&artists=Loreen", "application/yaml")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/yaml") {
+		t.Errorf("Expected a YAML content type, got %s", contentType)
+	}
+
+	var response SearchResponse
+	if err := yaml.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if !strings.HasPrefix(w.Body.String(), "input:\n  title: Euphoria\n") {
+		t.Errorf("Expected the JSON field names in order, got:\n%s", w.Body.String())
+	}
+}
+
+func TestYAMLRender_QuotesAmbiguousStrings(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := yamlRender{map[string]any{"title": "Yes", "artists": []string{"no", "Loreen"}, "length": "1:00", "duration": 60}}.Render(w)
+	if err != nil {
+		t.Fatalf("Failed to render YAML: %v", err)
+	}
+
+	for _, line := range []string{`  - "no"`, `length: "1:00"`, `title: "Yes"`, "  - Loreen", "duration: 60"} {
+		if !strings.Contains(w.Body.String(), line+"\n") {
+			t.Errorf("Expected a line %q, got:\n%s", line, w.Body.String())
+		}
+	}
+}
+
+func TestNegotiateResponseFormat_MessagePack(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := negotiatedSearch(t, "/search?title=Euphoria&artists=Loreen", "application/x-msgpack")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/msgpack") {
+		t.Errorf("Expected a MessagePack content type, got %s", contentType)
+	}
+
+	var response SearchResponse
+	if err := codec.NewDecoderBytes(w.Body.Bytes(), new(codec.MsgpackHandle)).Decode(&response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.Input.Title != "Euphoria" || response.Video == nil || response.Video.ID != "euphoria123" {
+		t.Errorf("Expected the Euphoria video, got %+v", response)
+	}
+}
+
+func TestNegotiateResponseFormat_FormatParameter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := negotiatedSearch(t, "/search?title=Euphoria&artists=Loreen&format=yml", "*/*")
+
+	if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/yaml") {
+		t.Errorf("Expected a YAML content type, got %s", contentType)
+	}
+}
+
+func TestNegotiateResponseFormat_DefaultsToJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, accept := range []string{
+		"",
+		"*/*",
+		"text/html, application/json;q=0.9",
+		"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		"application/xml;q=0.1, application/json",
+		"application/xml, application/json",
+	} {
+		w := negotiatedSearch(t, "/search?title=Euphoria&artists=Loreen", accept)
+
+		if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+			t.Errorf("Expected a JSON content type for Accept %q, got %s", accept, contentType)
+		}
+	}
+}
+
+func TestNegotiateResponseFormat_QualityValues(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"application/json;q=0.5, application/xml", "application/xml"},
+		{"application/*;q=0.2, application/yaml", "application/yaml"},
+		{"text/html;q=0.5, application/xml;q=0.9, */*;q=0.1", "application/xml"},
+		{"application/json;q=0, application/x-msgpack;q=0.3", "application/msgpack"},
+	}
+
+	for _, tt := range tests {
+		w := negotiatedSearch(t, "/search?title=Euphoria&artists=Loreen", tt.accept)
+
+		if contentType := w.Header().Get("Content-Type"); !strings.HasPrefix(contentType, tt.contentType) {
+			t.Errorf("Expected %s for Accept %q, got %s", tt.contentType, tt.accept, contentType)
+		}
+	}
+}
+
+func TestNegotiateResponseFormat_NotAcceptable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		target string
+		accept string
+	}{
+		{"unsupported Accept", "/search?title=Euphoria", "text/csv"},
+		{"every format refused", "/search?title=Euphoria", "application/json;q=0, */*;q=0"},
+		{"unsupported format", "/search?title=Euphoria&format=csv", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := negotiatedSearch(t, tt.target, tt.accept)
+
+			if w.Code != http.StatusNotAcceptable {
+				t.Errorf("Expected status %d, got %d", http.StatusNotAcceptable, w.Code)
+			}
+		})
+	}
+}
+
+func TestRespond_ErrorsUseNegotiatedFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := negotiatedSearch(t, "/search?title=", "application/xml")
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "<response><error>The title can&#39;t be empty.</error></response>") {
+		t.Errorf("Expected an XML error, got %s", w.Body.String())
+	}
+}
@@ -1,47 +1,106 @@
 package handlers
 
 import (
+	"errors"
 	"log"
+	"math"
 	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"youtube-music-video-api/internal/services"
 )
 
 type SearchInput struct {
-	Title   string   `json:"title"`
-	Artists []string `json:"artists"`
+	Title    string           `json:"title"`
+	Artists  []string         `json:"artists"`
+	Credits  services.Credits `json:"credits"`
+	Region   string           `json:"region,omitempty"`
+	Language string           `json:"lang,omitempty"`
+	Duration int              `json:"duration,omitempty"`
 }
 
 type SearchVideo struct {
-	ID  string `json:"id"`
-	URL string `json:"url"`
+	ID            string                 `json:"id"`
+	URL           string                 `json:"url"`
+	Title         string                 `json:"title,omitempty"`
+	Channel       string                 `json:"channel,omitempty"`
+	Duration      int                    `json:"duration,omitempty"`
+	Kind          string                 `json:"kind,omitempty"`
+	Similarity    *float64               `json:"similarity,omitempty"`
+	Confidence    *float64               `json:"confidence,omitempty"`
+	Availability  *services.Availability `json:"availability,omitempty"`
+	Embeddable    *bool                  `json:"embeddable,omitempty"`
+	AgeRestricted *bool                  `json:"ageRestricted,omitempty"`
 }
 
 type SearchResponse struct {
-	Input SearchInput  `json:"input"`
-	Video *SearchVideo `json:"video"`
+	Input         SearchInput                 `json:"input"`
+	Video         *SearchVideo                `json:"video"`
+	Audio         *SearchVideo                `json:"audio,omitempty"`
+	Reason        string                      `json:"reason,omitempty"`
+	Candidates    []*SearchVideo              `json:"candidates,omitempty"`
+	LowConfidence []*SearchVideo              `json:"lowConfidenceCandidates,omitempty"`
+	Skipped       []services.SkippedCandidate `json:"skipped,omitempty"`
 }
 
-var youtubeService = services.NewYouTubeService()
+var (
+	regionPattern   = regexp.MustCompile(`^[A-Z]{2}$`)
+	languagePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+)
+
+var (
+	serviceConfig  = services.ConfigFromEnv()
+	youtubeService = newYouTubeService()
+)
+
+func newYouTubeService() *services.YouTubeService {
+	ys, err := services.NewYouTubeServiceWithConfig(serviceConfig)
+	if err != nil {
+		log.Fatalf("Invalid outbound HTTP configuration: %v", err)
+	}
+	return ys
+}
 
 // SearchHandler godoc
 // @Summary Search for music videos
 // @Description Returns a list of music video search results
 // @Tags search
-// @Produce json
+// @Produce json,xml,application/yaml,application/msgpack
 // @Param title query string true "Title to search for"
 // @Param artists query string false "Artist name or comma-separated list of artists"
+// @Param region query string false "ISO 3166-1 alpha-2 region code, e.g. SE"
+// @Param lang query string false "Language code, e.g. sv or en-US"
+// @Param availability query bool false "Report whether the video is playable in region"
+// @Param playable query bool false "Skip candidates that aren't playable in region"
+// @Param embeddable query bool false "Skip candidates with embedding disabled"
+// @Param exclude_age_restricted query bool false "Skip age-restricted candidates"
+// @Param duration query string false "Expected track length in seconds or mm:ss"
+// @Param duration_tolerance query int false "Allowed duration difference in seconds"
+// @Param strict_duration query bool false "Skip candidates outside the duration tolerance instead of ranking them lower"
+// @Param min_confidence query number false "Return no video unless a candidate's confidence, between 0 and 1, reaches this"
+// @Param source query string false "youtube (default) searches youtube.com; music searches YouTube Music and also returns the song's audio track"
+// @Param include_candidates query bool false "List every ranked candidate with its similarity score"
+// @Param format query string false "Response format when the Accept header can't be set: json, xml, yaml or msgpack"
 // @Success 200 {object} SearchResponse
 // @Failure 400 {object} map[string]string
+// @Failure 406 {object} map[string]string
 // @Router /search [get]
 func SearchHandler(c *gin.Context) {
 	title := strings.TrimSpace(c.Query("title"))
 	artistsParam := strings.TrimSpace(c.Query("artists"))
 	
 	if title == "" {
-		c.JSON(http.StatusBadRequest, map[string]string{"error": "The title can't be empty."})
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The title can't be empty."})
+		return
+	}
+	
+	opts, err := parseSearchOptions(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
 		return
 	}
 	
@@ -56,27 +115,123 @@ func SearchHandler(c *gin.Context) {
 		}
 	}
 	
-	videoIDs, err := youtubeService.SearchVideos(title, artists)
+	resolution, err := youtubeService.Resolve(title, artists, opts)
 	if err != nil {
 		log.Printf("Error searching YouTube for title '%s' with artists %v: %v", title, artists, err)
-		c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to search YouTube"})
+		respond(c, http.StatusInternalServerError, map[string]string{"error": "Failed to search YouTube"})
 		return
 	}
 	
-	var video *SearchVideo
-	if len(videoIDs) > 0 {
-		video = &SearchVideo{
-			ID:  videoIDs[0],
-			URL: "https://www.youtube.com/watch?v=" + videoIDs[0],
-		}
-	}
-	
+	respond(c, http.StatusOK, newSearchResponse(c, title, artists, opts, resolution))
+}
+
+// newSearchResponse describes resolution, listing every candidate when the
+// request asks for include_candidates.
+func newSearchResponse(c *gin.Context, title string, artists []string, opts services.SearchOptions, resolution *services.Resolution) SearchResponse {
 	response := SearchResponse{
 		Input: SearchInput{
-			Title:   title,
-			Artists: artists,
+			Title:    title,
+			Artists:  artists,
+			Credits:  services.ParseCredits(title, artists),
+			Region:   opts.Region,
+			Language: opts.Language,
+			Duration: int(opts.Duration.Seconds()),
 		},
-		Video: video,
+		Video:   newSearchVideo(resolution.Video),
+		Audio:   newSearchVideo(resolution.Audio),
+		Reason:  resolution.Reason,
+		Skipped: resolution.Skipped,
+	}
+	
+	for _, candidate := range resolution.LowConfidence {
+		response.LowConfidence = append(response.LowConfidence, newSearchVideo(candidate))
 	}
-	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+	
+	if c.Query("include_candidates") == "true" {
+		for _, candidate := range resolution.Candidates {
+			response.Candidates = append(response.Candidates, newSearchVideo(candidate))
+		}
+	}
+	
+	return response
+}
+
+// parseSearchOptions reads the optional search parameters shared by the
+// endpoints that resolve a song. Errors are suitable for returning to the
+// client.
+func parseSearchOptions(c *gin.Context) (services.SearchOptions, error) {
+	opts := services.SearchOptions{
+		Region:               strings.ToUpper(strings.TrimSpace(c.Query("region"))),
+		Language:             strings.TrimSpace(c.Query("lang")),
+		CheckAvailability:    c.Query("availability") == "true",
+		RequirePlayable:      c.Query("playable") == "true",
+		RequireEmbeddable:    c.Query("embeddable") == "true",
+		ExcludeAgeRestricted: c.Query("exclude_age_restricted") == "true",
+		StrictDuration:       c.Query("strict_duration") == "true",
+		Source:               strings.ToLower(strings.TrimSpace(c.Query("source"))),
+	}
+	
+	if opts.Region != "" && !regionPattern.MatchString(opts.Region) {
+		return opts, errors.New("The region must be a two-letter country code.")
+	}
+	
+	if opts.Language != "" && !languagePattern.MatchString(opts.Language) {
+		return opts, errors.New("The lang must be a language code such as en or en-US.")
+	}
+	
+	if opts.Source != "" && opts.Source != services.SourceYouTube && opts.Source != services.SourceMusic {
+		return opts, errors.New("The source must be youtube or music.")
+	}
+	
+	if value := strings.TrimSpace(c.Query("duration")); value != "" {
+		duration, err := services.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			return opts, errors.New("The duration must be in seconds or mm:ss.")
+		}
+		opts.Duration = duration
+	}
+	
+	if value := strings.TrimSpace(c.Query("duration_tolerance")); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			return opts, errors.New("The duration_tolerance must be a number of seconds.")
+		}
+		tolerance := time.Duration(seconds) * time.Second
+		opts.DurationTolerance = &tolerance
+	}
+	
+	if value := strings.TrimSpace(c.Query("min_confidence")); value != "" {
+		confidence, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(confidence) || confidence < 0 || confidence > 1 {
+			return opts, errors.New("The min_confidence must be a number between 0 and 1.")
+		}
+		opts.MinConfidence = &confidence
+	}
+	
+	return opts, nil
+}
+
+// newSearchVideo describes candidate, linking audio tracks to YouTube Music
+// and videos to YouTube.
+func newSearchVideo(candidate *services.Candidate) *SearchVideo {
+	if candidate == nil {
+		return nil
+	}
+	watchURL := "https://www.youtube.com/watch?v=" + candidate.ID
+	if candidate.Kind == services.KindArtTrack {
+		watchURL = "https://music.youtube.com/watch?v=" + candidate.ID
+	}
+	return &SearchVideo{
+		ID:            candidate.ID,
+		URL:           watchURL,
+		Title:         candidate.Title,
+		Channel:       candidate.Channel,
+		Duration:      candidate.Duration,
+		Kind:          candidate.Kind,
+		Similarity:    candidate.Similarity,
+		Confidence:    candidate.Confidence,
+		Availability:  candidate.Availability,
+		Embeddable:    candidate.Embeddable,
+		AgeRestricted: candidate.AgeRestricted,
+	}
+}
@@ -9,8 +9,32 @@ import (
 	"testing"
 
 	"github.com/gin-gonic/gin"
+	"youtube-music-video-api/internal/services"
 )
 
+// useStubYouTube points the handlers at a local stand-in for youtube.com for
+// the duration of the test.
+func useStubYouTube(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := services.DefaultConfig()
+	config.BaseURL = server.URL
+	config.MusicBaseURL = server.URL
+	config.ThumbnailBaseURL = server.URL
+	config.ThumbnailCacheDir = t.TempDir()
+	ys, err := services.NewYouTubeServiceWithConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to create YouTube service: %v", err)
+	}
+
+	original := youtubeService
+	youtubeService = ys
+	t.Cleanup(func() { youtubeService = original })
+}
+
 func TestSearchHandler_ValidRequest(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	
@@ -324,4 +348,384 @@ func TestSearchHandler_ResponseStructure(t *testing.T) {
 			t.Errorf("Expected YouTube URL format, got %s", response.Video.URL)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestSearchHandler_RegionAndLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var upstream url.Values
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		upstream = r.URL.Query()
+		w.Write([]byte(`{"videoId":"regional123"}`))
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = &http.Request{
+		URL: &url.URL{
+			RawQuery: "title=Euphoria&artists=Loreen&region=se&lang=sv",
+		},
+	}
+
+	SearchHandler(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response SearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Input.Region != "SE" || response.Input.Language != "sv" {
+		t.Errorf("Expected region SE and lang sv, got %q and %q", response.Input.Region, response.Input.Language)
+	}
+	if upstream.Get("gl") != "SE" || upstream.Get("hl") != "sv" {
+		t.Errorf("Expected gl=SE&hl=sv upstream, got %v", upstream)
+	}
+	if response.Video == nil || response.Video.ID != "regional123" {
+		t.Errorf("Expected video regional123, got %+v", response.Video)
+	}
+}
+
+func TestSearchHandler_InvalidRegionAndLanguage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, query := range []string{"title=Test&region=SWE", "title=Test&region=1A", "title=Test&lang=english!"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{URL: &url.URL{RawQuery: query}}
+
+		SearchHandler(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %q, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
+
+func TestSearchHandler_PlayableInRegion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("v") {
+		case "":
+			w.Write([]byte(`{"videoId":"blockedUS"} {"videoId":"openUS"}`))
+		case "blockedUS":
+			w.Write([]byte(`var ytInitialPlayerResponse = {"playabilityStatus":{"status":"OK"},"microformat":{"playerMicroformatRenderer":{"availableCountries":["SE"]}}};`))
+		default:
+			w.Write([]byte(`var ytInitialPlayerResponse = {"playabilityStatus":{"status":"OK"}};`))
+		}
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = &http.Request{
+		URL: &url.URL{
+			RawQuery: "title=Test&region=US&playable=true",
+		},
+	}
+
+	SearchHandler(c)
+
+	var response SearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Video == nil || response.Video.ID != "openUS" {
+		t.Fatalf("Expected openUS, got %+v", response.Video)
+	}
+	if response.Video.Availability == nil || !response.Video.Availability.Playable {
+		t.Errorf("Expected playable availability, got %+v", response.Video.Availability)
+	}
+	if len(response.Skipped) != 1 || response.Skipped[0].ID != "blockedUS" {
+		t.Errorf("Expected blockedUS to be skipped, got %+v", response.Skipped)
+	}
+}
+
+func TestSearchHandler_EmbeddableOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("v") {
+		case "":
+			w.Write([]byte(`{"videoId":"noEmbed"} {"videoId":"embedOK"}`))
+		case "noEmbed":
+			w.Write([]byte(`var ytInitialPlayerResponse = {"playabilityStatus":{"status":"OK","playableInEmbed":false}};`))
+		default:
+			w.Write([]byte(`var ytInitialPlayerResponse = {"playabilityStatus":{"status":"OK","playableInEmbed":true}};`))
+		}
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = &http.Request{
+		URL: &url.URL{
+			RawQuery: "title=Test&embeddable=true",
+		},
+	}
+
+	SearchHandler(c)
+
+	var response SearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Video == nil || response.Video.ID != "embedOK" {
+		t.Fatalf("Expected embedOK, got %+v", response.Video)
+	}
+	if response.Video.Embeddable == nil || !*response.Video.Embeddable {
+		t.Errorf("Expected embeddable=true, got %v", response.Video.Embeddable)
+	}
+}
+
+func TestSearchHandler_Credits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var query string
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("search_query")
+		w.Write([]byte(`{"videoId":"stay123"}`))
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = &http.Request{
+		URL: &url.URL{
+			RawQuery: url.Values{
+				"title":   {"Stay (with Justin Bieber)"},
+				"artists": {"The Kid LAROI & Justin Bieber"},
+			}.Encode(),
+		},
+	}
+
+	SearchHandler(c)
+
+	var response SearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	credits := response.Input.Credits
+	if credits.Title != "Stay" {
+		t.Errorf("Expected credits title 'Stay', got %q", credits.Title)
+	}
+	if len(credits.Primary) != 1 || credits.Primary[0] != "The Kid LAROI" {
+		t.Errorf("Expected primary [The Kid LAROI], got %v", credits.Primary)
+	}
+	if len(credits.Featured) != 1 || credits.Featured[0] != "Justin Bieber" {
+		t.Errorf("Expected featured [Justin Bieber], got %v", credits.Featured)
+	}
+	if response.Input.Title != "Stay (with Justin Bieber)" {
+		t.Errorf("Expected original title to be echoed, got %q", response.Input.Title)
+	}
+	if query != "Stay The Kid LAROI Justin Bieber" {
+		t.Errorf("Expected query built from credits, got %q", query)
+	}
+}
+
+func TestSearchHandler_Duration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`var ytInitialData = {"contents":[` +
+			`{"videoRenderer":{"videoId":"loop","title":{"simpleText":"Song 10 hours"},"lengthText":{"simpleText":"10:00:00"}}},` +
+			`{"videoRenderer":{"videoId":"single","title":{"simpleText":"Song"},"lengthText":{"simpleText":"3:30"}}}]};`))
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = &http.Request{
+		URL: &url.URL{
+			RawQuery: "title=Song&duration=3:28",
+		},
+	}
+
+	SearchHandler(c)
+
+	var response SearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Input.Duration != 208 {
+		t.Errorf("Expected input duration 208, got %d", response.Input.Duration)
+	}
+	if response.Video == nil || response.Video.ID != "single" || response.Video.Duration != 210 {
+		t.Errorf("Expected single with duration 210, got %+v", response.Video)
+	}
+}
+
+func TestSearchHandler_InvalidDuration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, query := range []string{"title=Test&duration=abc", "title=Test&duration=0", "title=Test&duration=3:30&duration_tolerance=-1"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{URL: &url.URL{RawQuery: query}}
+
+		SearchHandler(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %q, got %d", http.StatusBadRequest, query, w.Code)
+		}
+	}
+}
+
+func TestSearchHandler_IncludeCandidates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`var ytInitialData = {"contents":[` +
+			`{"videoRenderer":{"videoId":"other","title":{"simpleText":"Something Else"},"ownerText":{"runs":[{"text":"Nobody"}]}}},` +
+			`{"videoRenderer":{"videoId":"kino","title":{"simpleText":"Kino - Gruppa Krovi"},"ownerText":{"runs":[{"text":"Kino"}]}}}]};`))
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = &http.Request{
+		URL: &url.URL{
+			RawQuery: url.Values{
+				"title":              {"Группа крови"},
+				"artists":            {"Кино"},
+				"include_candidates": {"true"},
+			}.Encode(),
+		},
+	}
+
+	SearchHandler(c)
+
+	var response SearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Video == nil || response.Video.ID != "kino" {
+		t.Fatalf("Expected romanized upload to win, got %+v", response.Video)
+	}
+	if len(response.Candidates) != 2 {
+		t.Fatalf("Expected 2 candidates, got %d", len(response.Candidates))
+	}
+	for _, candidate := range response.Candidates {
+		if candidate.Similarity == nil {
+			t.Errorf("Expected similarity for candidate %s", candidate.ID)
+		}
+	}
+	if *response.Candidates[0].Similarity <= *response.Candidates[1].Similarity {
+		t.Errorf("Expected candidates ordered by similarity, got %v then %v", *response.Candidates[0].Similarity, *response.Candidates[1].Similarity)
+	}
+}
+
+func TestSearchHandler_MinConfidence(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`var ytInitialData = {"contents":[` +
+			`{"videoRenderer":{"videoId":"other","title":{"simpleText":"Something Else"},"ownerText":{"runs":[{"text":"Nobody"}]}}}]};`))
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = &http.Request{
+		URL: &url.URL{
+			RawQuery: url.Values{
+				"title":          {"Euphoria"},
+				"artists":        {"Loreen"},
+				"min_confidence": {"0.7"},
+			}.Encode(),
+		},
+	}
+
+	SearchHandler(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response SearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Video != nil {
+		t.Errorf("Expected no video, got %+v", response.Video)
+	}
+	if response.Reason == "" {
+		t.Error("Expected a reason for the missing video")
+	}
+	if len(response.LowConfidence) != 1 || response.LowConfidence[0].Confidence == nil {
+		t.Errorf("Expected the candidate with its confidence, got %+v", response.LowConfidence)
+	}
+}
+
+func TestSearchHandler_InvalidMinConfidence(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, value := range []string{"high", "-0.1", "1.5", "NaN"} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = &http.Request{
+			URL: &url.URL{RawQuery: url.Values{"title": {"Euphoria"}, "min_confidence": {value}}.Encode()},
+		}
+
+		SearchHandler(c)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %q, got %d", http.StatusBadRequest, value, w.Code)
+		}
+	}
+}
+
+func TestSearchHandler_MusicSource(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	item := func(id, title, musicVideoType string) string {
+		return `{"musicResponsiveListItemRenderer":{"playlistItemData":{"videoId":"` + id + `"},` +
+			`"overlay":{"musicItemThumbnailOverlayRenderer":{"content":{"musicPlayButtonRenderer":{"playNavigationEndpoint":{"watchEndpoint":{"videoId":"` + id + `",` +
+			`"watchEndpointMusicSupportedConfigs":{"watchEndpointMusicConfig":{"musicVideoType":"` + musicVideoType + `"}}}}}}}},` +
+			`"flexColumns":[{"musicResponsiveListItemFlexColumnRenderer":{"text":{"runs":[{"text":"` + title + `"}]}}},` +
+			`{"musicResponsiveListItemFlexColumnRenderer":{"text":{"runs":[{"text":"Loreen"},{"text":" • "},{"text":"3:01"}]}}}]}}`
+	}
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/youtubei/v1/search" {
+			t.Errorf("Expected a YouTube Music search, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"contents":[` +
+			`{"musicShelfRenderer":{"title":{"runs":[{"text":"Songs"}]},"contents":[` + item("euphoriaATV", "Euphoria", "MUSIC_VIDEO_TYPE_ATV") + `]}},` +
+			`{"musicShelfRenderer":{"title":{"runs":[{"text":"Videos"}]},"contents":[` + item("euphoriaOMV", "Euphoria", "MUSIC_VIDEO_TYPE_OMV") + `]}}]}`))
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = &http.Request{URL: &url.URL{RawQuery: "title=Euphoria&artists=Loreen&source=music"}}
+
+	SearchHandler(c)
+
+	var response SearchResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if response.Video == nil || response.Video.ID != "euphoriaOMV" || response.Video.Kind != "official_video" {
+		t.Errorf("Expected the official video, got %+v", response.Video)
+	}
+	if response.Audio == nil || response.Audio.URL != "https://music.youtube.com/watch?v=euphoriaATV" || response.Audio.Kind != "art_track" {
+		t.Errorf("Expected the art track on YouTube Music, got %+v", response.Audio)
+	}
+}
+
+func TestSearchHandler_InvalidSource(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = &http.Request{URL: &url.URL{RawQuery: "title=Euphoria&source=spotify"}}
+
+	SearchHandler(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
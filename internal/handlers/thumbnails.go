@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"youtube-music-video-api/internal/services"
+)
+
+const (
+	// maxThumbnailDimension bounds resized thumbnails to the size of the
+	// largest variant YouTube serves.
+	maxThumbnailDimension = 1280
+
+	thumbnailMaxAge = 24 * time.Hour
+)
+
+// ThumbnailHandler godoc
+// @Summary Get a video's thumbnail
+// @Description Proxies a thumbnail from i.ytimg.com. When the video has no thumbnail of the requested quality, the next smaller one is returned. Thumbnails can be resized to fit within width and height. WebP thumbnails are YouTube's own WebP variant when it has one and no resizing is asked for, and otherwise the JPEG converted to lossless WebP. Images are cached on disk.
+// @Tags videos
+// @Produce image/jpeg,image/webp
+// @Param id path string true "YouTube video ID"
+// @Param quality query string false "default (120x90), mq (320x180), hq (480x360, default), sd (640x480) or maxres (1280x720)"
+// @Param format query string false "jpeg (default) or webp"
+// @Param width query int false "Maximum width in pixels, at most 1280"
+// @Param height query int false "Maximum height in pixels, at most 1280"
+// @Success 200 {file} file
+// @Success 304
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /thumbnails/{id} [get]
+func ThumbnailHandler(c *gin.Context) {
+	id := c.Param("id")
+	if !services.IsVideoID(id) {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The id must be an 11-character YouTube video ID."})
+		return
+	}
+
+	opts := services.ThumbnailOptions{Quality: strings.ToLower(strings.TrimSpace(c.DefaultQuery("quality", "hq")))}
+	if !slices.Contains(services.ThumbnailQualities, opts.Quality) {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The quality must be default, mq, hq, sd or maxres."})
+		return
+	}
+
+	contentType := "image/jpeg"
+	switch strings.ToLower(strings.TrimSpace(c.DefaultQuery("format", "jpeg"))) {
+	case "jpeg", "jpg":
+	case "webp":
+		opts.WebP = true
+		contentType = "image/webp"
+	default:
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The format must be jpeg or webp."})
+		return
+	}
+
+	var err error
+	if opts.Width, err = parseThumbnailDimension(c, "width"); err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if opts.Height, err = parseThumbnailDimension(c, "height"); err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	data, err := youtubeService.GetThumbnail(id, opts)
+	if errors.Is(err, services.ErrThumbnailNotFound) {
+		respond(c, http.StatusNotFound, map[string]string{"error": "The video has no thumbnail."})
+		return
+	}
+	if err != nil {
+		log.Printf("Error fetching thumbnail for video %s: %v", id, err)
+		respond(c, http.StatusInternalServerError, map[string]string{"error": "Failed to fetch the thumbnail"})
+		return
+	}
+
+	sum := sha256.Sum256(data)
+	c.Header("Content-Type", contentType)
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(thumbnailMaxAge.Seconds())))
+	c.Header("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	// ServeContent answers If-None-Match with 304 Not Modified.
+	http.ServeContent(c.Writer, c.Request, "", time.Time{}, bytes.NewReader(data))
+}
+
+// parseThumbnailDimension reads an optional width or height of at most
+// maxThumbnailDimension pixels.
+func parseThumbnailDimension(c *gin.Context, name string) (int, error) {
+	pixels, err := parseDimension(c, name)
+	if err != nil {
+		return 0, err
+	}
+	if pixels > maxThumbnailDimension {
+		return 0, fmt.Errorf("The %s can be at most %d pixels.", name, maxThumbnailDimension)
+	}
+	return pixels, nil
+}
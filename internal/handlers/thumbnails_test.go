@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/image/webp"
+)
+
+func thumbnailRequest(target, ifNoneMatch string) *httptest.ResponseRecorder {
+	// A router is needed for a 304 without a body to be written.
+	r := gin.New()
+	r.GET("/thumbnails/:id", ThumbnailHandler)
+
+	req := httptest.NewRequest("GET", target, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestThumbnailHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var thumbnail bytes.Buffer
+	jpeg.Encode(&thumbnail, image.NewGray(image.Rect(0, 0, 480, 360)), nil)
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/vi/dQw4w9WgXcQ/hqdefault.jpg" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(thumbnail.Bytes())
+	})
+
+	w := thumbnailRequest("/thumbnails/dQw4w9WgXcQ?quality=maxres&width=120", "")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "image/jpeg" {
+		t.Errorf("Expected image/jpeg, got %s", contentType)
+	}
+	if cacheControl := w.Header().Get("Cache-Control"); cacheControl != "public, max-age=86400" {
+		t.Errorf("Expected a public Cache-Control, got %q", cacheControl)
+	}
+
+	config, err := jpeg.DecodeConfig(w.Body)
+	if err != nil {
+		t.Fatalf("Failed to decode thumbnail: %v", err)
+	}
+	if config.Width != 120 || config.Height != 90 {
+		t.Errorf("Expected a 120x90 thumbnail, got %dx%d", config.Width, config.Height)
+	}
+
+	w = thumbnailRequest("/thumbnails/dQw4w9WgXcQ?format=webp&width=120", "")
+	if contentType := w.Header().Get("Content-Type"); w.Code != http.StatusOK || contentType != "image/webp" {
+		t.Fatalf("Expected a WebP thumbnail, got status %d with %s", w.Code, contentType)
+	}
+	if config, err := webp.DecodeConfig(w.Body); err != nil || config.Width != 120 || config.Height != 90 {
+		t.Errorf("Expected a 120x90 WebP thumbnail, got %dx%d (%v)", config.Width, config.Height, err)
+	}
+
+	w = thumbnailRequest("/thumbnails/dQw4w9WgXcQ?quality=maxres&width=120", "")
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected an ETag")
+	}
+	w = thumbnailRequest("/thumbnails/dQw4w9WgXcQ?quality=maxres&width=120", etag)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected status %d for a matching ETag, got %d", http.StatusNotModified, w.Code)
+	}
+
+	w = thumbnailRequest("/thumbnails/xxxxxxxxxxx", "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d for a missing video, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestThumbnailHandler_InvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, target := range []string{
+		"/thumbnails/not-an-id",
+		"/thumbnails/dQw4w9WgXcQ?quality=huge",
+		"/thumbnails/dQw4w9WgXcQ?format=png",
+		"/thumbnails/dQw4w9WgXcQ?width=0",
+		"/thumbnails/dQw4w9WgXcQ?height=4000",
+	} {
+		w := thumbnailRequest(target, "")
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", http.StatusBadRequest, target, w.Code)
+		}
+	}
+}
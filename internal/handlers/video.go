@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"youtube-music-video-api/internal/services"
+)
+
+type VideoResponse struct {
+	URL string `json:"url"`
+	services.VideoDetails
+}
+
+// VideoHandler godoc
+// @Summary Get the metadata of a video
+// @Description Returns a video's title, channel, duration, publish date, view count, thumbnails and, when YouTube lists it, the music used in the video
+// @Tags videos
+// @Produce json,xml,application/yaml,application/msgpack
+// @Param id path string true "YouTube video ID"
+// @Param format query string false "Response format when the Accept header can't be set: json, xml, yaml or msgpack"
+// @Success 200 {object} VideoResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 406 {object} map[string]string
+// @Router /videos/{id} [get]
+func VideoHandler(c *gin.Context) {
+	id := c.Param("id")
+	if !services.IsVideoID(id) {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The id must be an 11-character YouTube video ID."})
+		return
+	}
+
+	details, err := youtubeService.GetVideoDetails(id)
+	if errors.Is(err, services.ErrVideoNotFound) {
+		respond(c, http.StatusNotFound, map[string]string{"error": "The video doesn't exist."})
+		return
+	}
+	if err != nil {
+		log.Printf("Error fetching details for video %s: %v", id, err)
+		respond(c, http.StatusInternalServerError, map[string]string{"error": "Failed to fetch the video"})
+		return
+	}
+
+	respond(c, http.StatusOK, VideoResponse{
+		URL:          "https://www.youtube.com/watch?v=" + details.ID,
+		VideoDetails: *details,
+	})
+}
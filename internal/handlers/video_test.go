@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestVideoHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	useStubYouTube(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("v") != "dQw4w9WgXcQ" {
+			w.Write([]byte(`<script>var ytInitialPlayerResponse = {"playabilityStatus":{"status":"ERROR"}};</script>`))
+			return
+		}
+		w.Write([]byte(`<script>var ytInitialPlayerResponse = {"playabilityStatus":{"status":"OK"},` +
+			`"videoDetails":{"videoId":"dQw4w9WgXcQ","title":"Never Gonna Give You Up","author":"Rick Astley","lengthSeconds":"213","viewCount":"42"}};</script>`))
+	})
+
+	tests := []struct {
+		id     string
+		status int
+	}{
+		{"dQw4w9WgXcQ", http.StatusOK},
+		{"xxxxxxxxxxx", http.StatusNotFound},
+		{"not-a-video-id", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/videos/"+tt.id, nil)
+		c.Params = gin.Params{{Key: "id", Value: tt.id}}
+
+		VideoHandler(c)
+
+		if w.Code != tt.status {
+			t.Errorf("Expected status %d for %q, got %d", tt.status, tt.id, w.Code)
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+
+		var response VideoResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if response.URL != "https://www.youtube.com/watch?v=dQw4w9WgXcQ" {
+			t.Errorf("Unexpected URL %q", response.URL)
+		}
+		if response.Title != "Never Gonna Give You Up" || response.Duration != 213 || response.ViewCount != 42 {
+			t.Errorf("Unexpected video %+v", response.VideoDetails)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WatchHandler godoc
+// @Summary Redirect to the music video for a song
+// @Description Resolves a song and redirects to its video on YouTube, YouTube Music or the embeddable player. When no video is found, the client is redirected to the configured fallback page, or else to the search results for the song.
+// @Tags watch
+// @Param title query string true "Title to search for"
+// @Param artists query string false "Artist name or comma-separated list of artists"
+// @Param target query string false "watch (default), music or embed"
+// @Param region query string false "ISO 3166-1 alpha-2 region code, e.g. SE"
+// @Param lang query string false "Language code, e.g. sv or en-US"
+// @Param playable query bool false "Skip candidates that aren't playable in region"
+// @Param duration query string false "Expected track length in seconds or mm:ss"
+// @Param min_confidence query number false "Redirect to the fallback page unless a candidate's confidence, between 0 and 1, reaches this"
+// @Success 302
+// @Failure 400 {object} map[string]string
+// @Router /watch [get]
+func WatchHandler(c *gin.Context) {
+	title := strings.TrimSpace(c.Query("title"))
+	if title == "" {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The title can't be empty."})
+		return
+	}
+	artists := splitArtists(c.Query("artists"))
+
+	target := strings.ToLower(strings.TrimSpace(c.DefaultQuery("target", "watch")))
+	if target != "watch" && target != "music" && target != "embed" {
+		respond(c, http.StatusBadRequest, map[string]string{"error": "The target must be watch, music or embed."})
+		return
+	}
+
+	opts, err := parseSearchOptions(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if target == "embed" {
+		opts.RequireEmbeddable = true
+	}
+
+	query := strings.Join(append([]string{title}, artists...), " ")
+
+	resolution, err := youtubeService.Resolve(title, artists, opts)
+	if err != nil {
+		log.Printf("Error searching YouTube for title '%s' with artists %v: %v", title, artists, err)
+		c.Redirect(http.StatusFound, watchFallbackURL(target, query))
+		return
+	}
+	if resolution.Video == nil {
+		c.Redirect(http.StatusFound, watchFallbackURL(target, query))
+		return
+	}
+
+	id := resolution.Video.ID
+	switch target {
+	case "music":
+		c.Redirect(http.StatusFound, "https://music.youtube.com/watch?v="+id)
+	case "embed":
+		c.Redirect(http.StatusFound, "https://www.youtube.com/embed/"+id)
+	default:
+		c.Redirect(http.StatusFound, "https://www.youtube.com/watch?v="+id)
+	}
+}
+
+// watchFallbackURL returns the page for a song that wasn't found: the
+// configured fallback page, or else the search results for query on the
+// target's site.
+func watchFallbackURL(target, query string) string {
+	if serviceConfig.WatchFallbackURL != "" {
+		return strings.ReplaceAll(serviceConfig.WatchFallbackURL, "{query}", url.QueryEscape(query))
+	}
+	if target == "music" {
+		return "https://music.youtube.com/search?q=" + url.QueryEscape(query)
+	}
+	return "https://www.youtube.com/results?search_query=" + url.QueryEscape(query)
+}
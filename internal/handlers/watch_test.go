@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func watchRequest(query url.Values) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/watch?"+query.Encode(), nil)
+
+	WatchHandler(c)
+	return w
+}
+
+func TestWatchHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	useStubEmbeds(t)
+
+	tests := []struct {
+		name     string
+		target   string
+		location string
+	}{
+		{"default", "", "https://www.youtube.com/watch?v=noEmbed0000"},
+		{"music", "music", "https://music.youtube.com/watch?v=noEmbed0000"},
+		// The first result can't be embedded.
+		{"embed", "embed", "https://www.youtube.com/embed/euphoria123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := url.Values{"title": {"Euphoria"}, "artists": {"Loreen"}}
+			if tt.target != "" {
+				query.Set("target", tt.target)
+			}
+
+			w := watchRequest(query)
+
+			if w.Code != http.StatusFound {
+				t.Fatalf("Expected status %d, got %d: %s", http.StatusFound, w.Code, w.Body.String())
+			}
+			if location := w.Header().Get("Location"); location != tt.location {
+				t.Errorf("Expected redirect to %s, got %s", tt.location, location)
+			}
+		})
+	}
+}
+
+func TestWatchHandler_Fallback(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	useStubEmbeds(t)
+
+	// Only the unembeddable video is found for this song.
+	query := url.Values{"title": {"Nothing"}, "artists": {"Nobody"}, "target": {"embed"}}
+
+	w := watchRequest(query)
+	if location := w.Header().Get("Location"); location != "https://www.youtube.com/results?search_query=Nothing+Nobody" {
+		t.Errorf("Expected redirect to the search results, got %s", location)
+	}
+
+	original := serviceConfig.WatchFallbackURL
+	serviceConfig.WatchFallbackURL = "https://example.com/not-found?q={query}"
+	t.Cleanup(func() { serviceConfig.WatchFallbackURL = original })
+
+	w = watchRequest(query)
+	if w.Code != http.StatusFound {
+		t.Fatalf("Expected status %d, got %d", http.StatusFound, w.Code)
+	}
+	if location := w.Header().Get("Location"); location != "https://example.com/not-found?q=Nothing+Nobody" {
+		t.Errorf("Expected redirect to the fallback page, got %s", location)
+	}
+}
+
+func TestWatchHandler_InvalidRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{"no title", url.Values{"artists": {"Loreen"}}},
+		{"unknown target", url.Values{"title": {"Euphoria"}, "target": {"spotify"}}},
+		{"invalid region", url.Values{"title": {"Euphoria"}, "region": {"Sweden"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := watchRequest(tt.query)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+		})
+	}
+}
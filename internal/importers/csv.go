@@ -0,0 +1,156 @@
+package importers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"youtube-music-video-api/internal/services"
+)
+
+// CSVColumns names the header of each column a CSVReader reads. Empty
+// names fall back to the defaults in DefaultCSVColumns.
+type CSVColumns struct {
+	Title    string
+	Artists  string
+	Duration string
+	ID       string
+}
+
+// DefaultCSVColumns lists the header names tried for each column, in
+// order, when CSVColumns leaves it empty.
+var DefaultCSVColumns = struct {
+	Title, Artists, Duration, ID []string
+}{
+	Title:    []string{"title", "track", "song", "name"},
+	Artists:  []string{"artists", "artist"},
+	Duration: []string{"duration", "length"},
+	ID:       []string{"id", "external_id", "isrc"},
+}
+
+var csvArtistSeparator = regexp.MustCompile(`\s*[,;]\s*`)
+
+// CSVRow is a parsed data row. Record is the row as read, so it can be
+// written back out with extra columns.
+type CSVRow struct {
+	Record     []string
+	Track      Track
+	ExternalID string
+}
+
+// CSVReader reads tracks from a CSV file with a header row, one row at a
+// time.
+type CSVReader struct {
+	reader *csv.Reader
+	header []string
+
+	title, artists, duration, id int
+}
+
+// NewCSVReader reads the header from r and locates the mapped columns. The
+// title column is required unless an ID column is present.
+func NewCSVReader(r io.Reader, columns CSVColumns, delimiter rune) (*CSVReader, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("the CSV is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\uFEFF")
+	}
+
+	cr := &CSVReader{reader: reader, header: header}
+
+	lookups := []struct {
+		target   *int
+		name     string
+		defaults []string
+	}{
+		{&cr.title, columns.Title, DefaultCSVColumns.Title},
+		{&cr.artists, columns.Artists, DefaultCSVColumns.Artists},
+		{&cr.duration, columns.Duration, DefaultCSVColumns.Duration},
+		{&cr.id, columns.ID, DefaultCSVColumns.ID},
+	}
+	for _, lookup := range lookups {
+		if lookup.name != "" {
+			*lookup.target = columnIndex(header, lookup.name)
+			if *lookup.target == -1 {
+				return nil, fmt.Errorf("the CSV has no %q column", lookup.name)
+			}
+			continue
+		}
+
+		*lookup.target = -1
+		for _, name := range lookup.defaults {
+			if index := columnIndex(header, name); index != -1 {
+				*lookup.target = index
+				break
+			}
+		}
+	}
+
+	if cr.title == -1 && cr.id == -1 {
+		return nil, errors.New("the CSV has no title or ID column")
+	}
+
+	return cr, nil
+}
+
+// Header returns the header row as read.
+func (cr *CSVReader) Header() []string {
+	return cr.header
+}
+
+// Next returns the next data row, or io.EOF after the last one.
+func (cr *CSVReader) Next() (*CSVRow, error) {
+	record, err := cr.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	row := &CSVRow{
+		Record:     record,
+		Track:      Track{Title: cell(record, cr.title)},
+		ExternalID: cell(record, cr.id),
+	}
+
+	if artists := cell(record, cr.artists); artists != "" {
+		for _, artist := range csvArtistSeparator.Split(artists, -1) {
+			if artist != "" {
+				row.Track.Artists = append(row.Track.Artists, artist)
+			}
+		}
+	}
+
+	if value := cell(record, cr.duration); value != "" {
+		if duration, err := services.ParseDuration(value); err == nil {
+			row.Track.Duration = int(duration.Seconds())
+		}
+	}
+
+	return row, nil
+}
+
+func columnIndex(header []string, name string) int {
+	return slices.IndexFunc(header, func(column string) bool {
+		return strings.EqualFold(strings.TrimSpace(column), strings.TrimSpace(name))
+	})
+}
+
+func cell(record []string, index int) string {
+	if index < 0 || index >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[index])
+}
@@ -0,0 +1,73 @@
+package importers
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCSVReader(t *testing.T) {
+	data := "\uFEFFTrack;Performer;Length;ISRC\n" +
+		"Euphoria;Loreen;3:04;SEAYD1200101\n" +
+		"Song;A, B;245\n" +
+		";;;USRC17607839\n"
+
+	reader, err := NewCSVReader(strings.NewReader(data), CSVColumns{Artists: "performer"}, ';')
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if header := reader.Header(); !reflect.DeepEqual(header, []string{"Track", "Performer", "Length", "ISRC"}) {
+		t.Errorf("Unexpected header %v", header)
+	}
+
+	expected := []CSVRow{
+		{
+			Record:     []string{"Euphoria", "Loreen", "3:04", "SEAYD1200101"},
+			Track:      Track{Title: "Euphoria", Artists: []string{"Loreen"}, Duration: 184},
+			ExternalID: "SEAYD1200101",
+		},
+		{
+			Record: []string{"Song", "A, B", "245"},
+			Track:  Track{Title: "Song", Artists: []string{"A", "B"}, Duration: 245},
+		},
+		{
+			Record:     []string{"", "", "", "USRC17607839"},
+			ExternalID: "USRC17607839",
+		},
+	}
+
+	for _, want := range expected {
+		row, err := reader.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(*row, want) {
+			t.Errorf("Expected %+v, got %+v", want, *row)
+		}
+	}
+
+	if _, err := reader.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestNewCSVReader_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		columns CSVColumns
+	}{
+		{"empty", "", CSVColumns{}},
+		{"no title or ID", "artist,duration\nLoreen,184\n", CSVColumns{}},
+		{"missing mapped column", "title,artist\nEuphoria,Loreen\n", CSVColumns{Title: "song"}},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewCSVReader(strings.NewReader(tt.data), tt.columns, ','); err == nil {
+			t.Errorf("Expected an error for %s", tt.name)
+		}
+	}
+}
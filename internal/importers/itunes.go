@@ -0,0 +1,197 @@
+package importers
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ITunesPlaylist is a user playlist of an iTunes library. Tracks are
+// indexes into ITunesLibrary.Tracks, in playlist order.
+type ITunesPlaylist struct {
+	Name   string
+	Tracks []int
+}
+
+// ITunesLibrary is the music of an iTunes or Apple Music Library.xml.
+type ITunesLibrary struct {
+	Tracks    []Track
+	Playlists []ITunesPlaylist
+}
+
+// ParseITunesLibrary parses an iTunes Library.xml property list. Podcasts,
+// movies and TV shows are left out, as are the built-in playlists such as
+// Library and Music and playlist folders.
+func ParseITunesLibrary(r io.Reader) (*ITunesLibrary, error) {
+	value, err := decodePlist(xml.NewDecoder(r))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse plist: %w", err)
+	}
+
+	root, ok := value.(map[string]any)
+	if !ok {
+		return nil, errors.New("the plist isn't an iTunes library")
+	}
+	entries, ok := root["Tracks"].(map[string]any)
+	if !ok {
+		return nil, errors.New("the plist has no Tracks")
+	}
+
+	library := &ITunesLibrary{}
+
+	// Tracks are listed in order of their IDs so the result doesn't depend
+	// on map iteration order.
+	ids := make([]int, 0, len(entries))
+	for key := range entries {
+		if id, err := strconv.Atoi(key); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	indexByID := make(map[int]int, len(ids))
+	for _, id := range ids {
+		entry, ok := entries[strconv.Itoa(id)].(map[string]any)
+		if !ok || plistBool(entry, "Podcast") || plistBool(entry, "Movie") || plistBool(entry, "TV Show") {
+			continue
+		}
+
+		track := Track{
+			Title:    strings.TrimSpace(plistString(entry, "Name")),
+			Album:    strings.TrimSpace(plistString(entry, "Album")),
+			Duration: int((plistInt(entry, "Total Time") + 500) / 1000),
+		}
+		if artist := strings.TrimSpace(plistString(entry, "Artist")); artist != "" {
+			track.Artists = []string{artist}
+		}
+
+		indexByID[id] = len(library.Tracks)
+		library.Tracks = append(library.Tracks, track)
+	}
+
+	playlists, _ := root["Playlists"].([]any)
+	for _, value := range playlists {
+		entry, ok := value.(map[string]any)
+		if !ok || plistBool(entry, "Master") || plistBool(entry, "Folder") {
+			continue
+		}
+		if _, builtIn := entry["Distinguished Kind"]; builtIn {
+			continue
+		}
+
+		playlist := ITunesPlaylist{Name: plistString(entry, "Name")}
+		items, _ := entry["Playlist Items"].([]any)
+		for _, item := range items {
+			itemEntry, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			if index, ok := indexByID[int(plistInt(itemEntry, "Track ID"))]; ok {
+				playlist.Tracks = append(playlist.Tracks, index)
+			}
+		}
+
+		library.Playlists = append(library.Playlists, playlist)
+	}
+
+	return library, nil
+}
+
+// decodePlist decodes the next property list value: dict becomes
+// map[string]any, array []any, integer int64, real float64, true and false
+// bool, and every other element its text.
+func decodePlist(decoder *xml.Decoder) (any, error) {
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local != "plist" {
+			return decodePlistElement(decoder, start)
+		}
+	}
+}
+
+func decodePlistElement(decoder *xml.Decoder, start xml.StartElement) (any, error) {
+	switch start.Name.Local {
+	case "dict":
+		dict := make(map[string]any)
+		var key string
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := token.(type) {
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					if err := decoder.DecodeElement(&key, &t); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				value, err := decodePlistElement(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				dict[key] = value
+			case xml.EndElement:
+				return dict, nil
+			}
+		}
+	case "array":
+		array := []any{}
+		for {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := token.(type) {
+			case xml.StartElement:
+				value, err := decodePlistElement(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				array = append(array, value)
+			case xml.EndElement:
+				return array, nil
+			}
+		}
+	case "true", "false":
+		if err := decoder.Skip(); err != nil {
+			return nil, err
+		}
+		return start.Name.Local == "true", nil
+	}
+
+	var text string
+	if err := decoder.DecodeElement(&text, &start); err != nil {
+		return nil, err
+	}
+	switch start.Name.Local {
+	case "integer":
+		return strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+	case "real":
+		return strconv.ParseFloat(strings.TrimSpace(text), 64)
+	}
+	return text, nil
+}
+
+func plistString(dict map[string]any, key string) string {
+	s, _ := dict[key].(string)
+	return s
+}
+
+func plistInt(dict map[string]any, key string) int64 {
+	n, _ := dict[key].(int64)
+	return n
+}
+
+func plistBool(dict map[string]any, key string) bool {
+	b, _ := dict[key].(bool)
+	return b
+}
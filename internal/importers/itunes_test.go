@@ -0,0 +1,94 @@
+package importers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testITunesLibrary = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Major Version</key><integer>1</integer>
+	<key>Tracks</key>
+	<dict>
+		<key>201</key>
+		<dict>
+			<key>Track ID</key><integer>201</integer>
+			<key>Name</key><string>Tattoo</string>
+			<key>Artist</key><string>Loreen</string>
+			<key>Album</key><string>Tattoo</string>
+			<key>Total Time</key><integer>183000</integer>
+		</dict>
+		<key>105</key>
+		<dict>
+			<key>Track ID</key><integer>105</integer>
+			<key>Name</key><string>Euphoria</string>
+			<key>Artist</key><string>Loreen</string>
+			<key>Album</key><string>Heal</string>
+			<key>Total Time</key><integer>184320</integer>
+			<key>Explicit</key><false/>
+		</dict>
+		<key>300</key>
+		<dict>
+			<key>Track ID</key><integer>300</integer>
+			<key>Name</key><string>Episode 1</string>
+			<key>Podcast</key><true/>
+		</dict>
+	</dict>
+	<key>Playlists</key>
+	<array>
+		<dict>
+			<key>Name</key><string>Library</string>
+			<key>Master</key><true/>
+			<key>Playlist Items</key>
+			<array>
+				<dict><key>Track ID</key><integer>105</integer></dict>
+				<dict><key>Track ID</key><integer>201</integer></dict>
+			</array>
+		</dict>
+		<dict>
+			<key>Name</key><string>Music</string>
+			<key>Distinguished Kind</key><integer>4</integer>
+		</dict>
+		<dict>
+			<key>Name</key><string>Eurovision</string>
+			<key>Playlist Items</key>
+			<array>
+				<dict><key>Track ID</key><integer>201</integer></dict>
+				<dict><key>Track ID</key><integer>105</integer></dict>
+				<dict><key>Track ID</key><integer>300</integer></dict>
+			</array>
+		</dict>
+	</array>
+</dict>
+</plist>`
+
+func TestParseITunesLibrary(t *testing.T) {
+	library, err := ParseITunesLibrary(strings.NewReader(testITunesLibrary))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedTracks := []Track{
+		{Title: "Euphoria", Artists: []string{"Loreen"}, Album: "Heal", Duration: 184},
+		{Title: "Tattoo", Artists: []string{"Loreen"}, Album: "Tattoo", Duration: 183},
+	}
+	if !reflect.DeepEqual(library.Tracks, expectedTracks) {
+		t.Errorf("Expected tracks %+v, got %+v", expectedTracks, library.Tracks)
+	}
+
+	expectedPlaylists := []ITunesPlaylist{{Name: "Eurovision", Tracks: []int{1, 0}}}
+	if !reflect.DeepEqual(library.Playlists, expectedPlaylists) {
+		t.Errorf("Expected playlists %+v, got %+v", expectedPlaylists, library.Playlists)
+	}
+}
+
+func TestParseITunesLibrary_Invalid(t *testing.T) {
+	for _, data := range []string{"not xml", `<plist><array></array></plist>`, `<plist><dict><key>Tracks</key>`} {
+		if _, err := ParseITunesLibrary(strings.NewReader(data)); err == nil {
+			t.Errorf("Expected an error for %q", data)
+		}
+	}
+}
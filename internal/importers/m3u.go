@@ -0,0 +1,69 @@
+package importers
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseM3U parses an M3U or M3U8 playlist. Entries with an #EXTINF line take
+// their duration and "Artist - Title" from it; plain entries are named
+// after their file.
+func ParseM3U(data []byte) []Track {
+	var tracks []Track
+	var info *Track
+
+	for line := range strings.Lines(decodeText(data)) {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#EXTINF:"):
+			track := parseExtInf(strings.TrimPrefix(line, "#EXTINF:"))
+			info = &track
+		case strings.HasPrefix(line, "#"):
+			continue
+		default:
+			if info != nil && info.Title != "" {
+				tracks = append(tracks, *info)
+			} else {
+				track := trackFromLocation(line)
+				if info != nil {
+					track.Duration = info.Duration
+				}
+				tracks = append(tracks, track)
+			}
+			info = nil
+		}
+	}
+
+	return tracks
+}
+
+// parseExtInf parses the part of an #EXTINF line after the colon:
+// `duration [key="value" ...],Artist - Title`.
+func parseExtInf(value string) Track {
+	comma := -1
+	quoted := false
+	for i, r := range value {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ',' && !quoted {
+			comma = i
+			break
+		}
+	}
+	if comma == -1 {
+		return Track{}
+	}
+
+	track := splitArtistTitle(value[comma+1:])
+
+	fields := strings.Fields(value[:comma])
+	if len(fields) > 0 {
+		if seconds, err := strconv.Atoi(fields[0]); err == nil && seconds > 0 {
+			track.Duration = seconds
+		}
+	}
+
+	return track
+}
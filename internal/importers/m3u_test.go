@@ -0,0 +1,65 @@
+package importers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseM3U(t *testing.T) {
+	data := []byte("#EXTM3U\n" +
+		"#EXTINF:184,Loreen - Euphoria\n" +
+		"Music/Loreen/Euphoria.mp3\n" +
+		"\n" +
+		"#EXTINF:-1 tvg-name=\"a, b\",Hello\n" +
+		"http://example.com/hello.mp3\n" +
+		"# A comment\n" +
+		"C:\\Music\\03 - Kino - Gruppa Krovi.flac\n")
+
+	expected := []Track{
+		{Title: "Euphoria", Artists: []string{"Loreen"}, Duration: 184},
+		{Title: "Hello"},
+		{Title: "Gruppa Krovi", Artists: []string{"Kino"}},
+	}
+
+	if tracks := ParseM3U(data); !reflect.DeepEqual(tracks, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, tracks)
+	}
+}
+
+func TestParseM3U_Latin1(t *testing.T) {
+	// "Beyoncé - Halo" encoded as Windows-1252.
+	data := []byte("#EXTINF:261,Beyonc\xe9 - Halo\nhalo.mp3\n")
+
+	tracks := ParseM3U(data)
+	if len(tracks) != 1 || tracks[0].Artists[0] != "Beyoncé" {
+		t.Errorf("Expected Windows-1252 to be decoded, got %+v", tracks)
+	}
+}
+
+func TestParseM3U_EmptyExtInfTitle(t *testing.T) {
+	tracks := ParseM3U([]byte("#EXTINF:200,\nLoreen - Tattoo.mp3\n"))
+
+	expected := []Track{{Title: "Tattoo", Artists: []string{"Loreen"}, Duration: 200}}
+	if !reflect.DeepEqual(tracks, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, tracks)
+	}
+}
+
+func TestParseM3U_TrackNumbers(t *testing.T) {
+	data := []byte("01 Euphoria.mp3\n" +
+		"2. Tattoo.mp3\n" +
+		"99 Luftballons.mp3\n" +
+		"7 Rings.mp3\n" +
+		"1999.mp3\n")
+
+	expected := []Track{
+		{Title: "Euphoria"},
+		{Title: "Tattoo"},
+		{Title: "99 Luftballons"},
+		{Title: "7 Rings"},
+		{Title: "1999"},
+	}
+	if tracks := ParseM3U(data); !reflect.DeepEqual(tracks, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, tracks)
+	}
+}
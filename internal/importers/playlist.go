@@ -0,0 +1,61 @@
+package importers
+
+import (
+	"bytes"
+	"path"
+	"strings"
+)
+
+// Playlist formats understood by ParsePlaylist.
+const (
+	FormatM3U  = "m3u"
+	FormatXSPF = "xspf"
+	FormatPLS  = "pls"
+)
+
+// DetectPlaylistFormat picks the format from the file extension, falling
+// back to sniffing the contents. It returns "" when neither is conclusive.
+func DetectPlaylistFormat(filename string, data []byte) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".m3u", ".m3u8":
+		return FormatM3U
+	case ".xspf":
+		return FormatXSPF
+	case ".pls":
+		return FormatPLS
+	}
+
+	head := bytes.ToLower(bytes.TrimSpace(trimBOM(data)))
+	switch {
+	case bytes.HasPrefix(head, []byte("#extm3u")):
+		return FormatM3U
+	case bytes.HasPrefix(head, []byte("[playlist]")):
+		return FormatPLS
+	case bytes.HasPrefix(head, []byte("<?xml")) || bytes.HasPrefix(head, []byte("<playlist")):
+		if bytes.Contains(head, []byte("xspf.org")) || bytes.Contains(head, []byte("<tracklist")) {
+			return FormatXSPF
+		}
+	}
+	return ""
+}
+
+// ParsePlaylist parses an M3U/M3U8, XSPF or PLS playlist and returns its
+// format along with its entries.
+func ParsePlaylist(filename string, data []byte) (string, []Track, error) {
+	format := DetectPlaylistFormat(filename, data)
+
+	var tracks []Track
+	var err error
+	switch format {
+	case FormatM3U:
+		tracks = ParseM3U(data)
+	case FormatXSPF:
+		tracks, err = ParseXSPF(data)
+	case FormatPLS:
+		tracks = ParsePLS(data)
+	default:
+		return "", nil, ErrUnknownFormat
+	}
+
+	return format, tracks, err
+}
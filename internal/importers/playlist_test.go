@@ -0,0 +1,45 @@
+package importers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDetectPlaylistFormat(t *testing.T) {
+	tests := []struct {
+		filename string
+		data     string
+		expected string
+	}{
+		{"mix.m3u", "", FormatM3U},
+		{"mix.M3U8", "", FormatM3U},
+		{"mix.xspf", "", FormatXSPF},
+		{"mix.pls", "", FormatPLS},
+		{"upload", "\uFEFF#EXTM3U\n", FormatM3U},
+		{"upload", "[playlist]\nFile1=a.mp3", FormatPLS},
+		{"upload", `<?xml version="1.0"?><playlist xmlns="http://xspf.org/ns/0/">`, FormatXSPF},
+		{"upload", `<playlist version="1"><trackList>`, FormatXSPF},
+		{"upload", "Loreen - Euphoria", ""},
+		{"upload", `<?xml version="1.0"?><plist>`, ""},
+	}
+
+	for _, tt := range tests {
+		if format := DetectPlaylistFormat(tt.filename, []byte(tt.data)); format != tt.expected {
+			t.Errorf("DetectPlaylistFormat(%q, %q) = %q; want %q", tt.filename, tt.data, format, tt.expected)
+		}
+	}
+}
+
+func TestParsePlaylist(t *testing.T) {
+	format, tracks, err := ParsePlaylist("mix.m3u8", []byte("#EXTM3U\n#EXTINF:184,Loreen - Euphoria\neuphoria.mp3\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if format != FormatM3U || len(tracks) != 1 {
+		t.Errorf("Unexpected result %q %+v", format, tracks)
+	}
+
+	if _, _, err := ParsePlaylist("notes.txt", []byte("hello")); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
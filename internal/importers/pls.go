@@ -0,0 +1,81 @@
+package importers
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ParsePLS parses a PLS playlist, whose entries are numbered FileN, TitleN
+// and LengthN keys.
+func ParsePLS(data []byte) []Track {
+	type entry struct {
+		file, title string
+		length      int
+	}
+	entries := make(map[int]*entry)
+
+	for line := range strings.Lines(decodeText(data)) {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		var field string
+		for _, prefix := range []string{"file", "title", "length"} {
+			if strings.HasPrefix(key, prefix) {
+				field = prefix
+				break
+			}
+		}
+		if field == "" {
+			continue
+		}
+
+		number, err := strconv.Atoi(strings.TrimPrefix(key, field))
+		if err != nil {
+			continue
+		}
+		if entries[number] == nil {
+			entries[number] = &entry{}
+		}
+
+		switch field {
+		case "file":
+			entries[number].file = value
+		case "title":
+			entries[number].title = value
+		case "length":
+			entries[number].length, _ = strconv.Atoi(value)
+		}
+	}
+
+	numbers := make([]int, 0, len(entries))
+	for number := range entries {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+
+	tracks := make([]Track, 0, len(numbers))
+	for _, number := range numbers {
+		e := entries[number]
+
+		var track Track
+		if e.title != "" {
+			track = splitArtistTitle(e.title)
+		} else if e.file != "" {
+			track = trackFromLocation(e.file)
+		} else {
+			continue
+		}
+		if e.length > 0 {
+			track.Duration = e.length
+		}
+
+		tracks = append(tracks, track)
+	}
+
+	return tracks
+}
@@ -0,0 +1,28 @@
+package importers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePLS(t *testing.T) {
+	data := []byte("[playlist]\n" +
+		"File2=http://example.com/stream\n" +
+		"Title2=Kino - Gruppa Krovi\n" +
+		"Length2=-1\n" +
+		"File1=/music/01 - Loreen - Euphoria.mp3\n" +
+		"Length1=184\n" +
+		"Title3=Hello\n" +
+		"NumberOfEntries=3\n" +
+		"Version=2\n")
+
+	expected := []Track{
+		{Title: "Euphoria", Artists: []string{"Loreen"}, Duration: 184},
+		{Title: "Gruppa Krovi", Artists: []string{"Kino"}},
+		{Title: "Hello"},
+	}
+
+	if tracks := ParsePLS(data); !reflect.DeepEqual(tracks, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, tracks)
+	}
+}
@@ -0,0 +1,261 @@
+package importers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"youtube-music-video-api/internal/services"
+)
+
+// Scrobble history sources understood by ReadScrobbles.
+const (
+	SourceLastFM       = "lastfm"
+	SourceListenBrainz = "listenbrainz"
+)
+
+// PlayCount is a track together with how often it was listened to.
+type PlayCount struct {
+	Track Track
+	Plays int
+}
+
+// PlayCounter aggregates listens by normalized title and artists, so
+// "Euphoria" by "LOREEN" and "Euphoria (Radio Edit)" by "Loreen" count as
+// the same song. Each song keeps the spelling of its first listen.
+type PlayCounter struct {
+	counts map[string]*PlayCount
+	order  []string
+	total  int
+}
+
+func NewPlayCounter() *PlayCounter {
+	return &PlayCounter{counts: make(map[string]*PlayCount)}
+}
+
+// Add counts one listen of track. Listens without a title are ignored.
+func (pc *PlayCounter) Add(track Track) {
+	if track.Title == "" {
+		return
+	}
+	pc.total++
+
+	credits := services.ParseCredits(track.Title, track.Artists)
+	key := services.NormalizeTitle(credits.Title) + "|" + strings.Join(services.NormalizeArtists(credits.Artists()), ",")
+
+	if count, ok := pc.counts[key]; ok {
+		count.Plays++
+		return
+	}
+	pc.counts[key] = &PlayCount{Track: track, Plays: 1}
+	pc.order = append(pc.order, key)
+}
+
+// Listens returns the number of listens counted.
+func (pc *PlayCounter) Listens() int {
+	return pc.total
+}
+
+// Tracks returns the number of distinct songs counted.
+func (pc *PlayCounter) Tracks() int {
+	return len(pc.order)
+}
+
+// Top returns the n most played songs, most played first. Songs with the
+// same play count keep the order they were first listened to in.
+func (pc *PlayCounter) Top(n int) []PlayCount {
+	top := make([]PlayCount, 0, len(pc.order))
+	for _, key := range pc.order {
+		top = append(top, *pc.counts[key])
+	}
+	slices.SortStableFunc(top, func(a, b PlayCount) int {
+		return b.Plays - a.Plays
+	})
+	return top[:min(n, len(top))]
+}
+
+// DetectScrobbleSource tells a ListenBrainz JSON export from a Last.fm CSV
+// export by its first character.
+func DetectScrobbleSource(r *bufio.Reader) string {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return SourceLastFM
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			r.ReadByte()
+		case 0xEF:
+			// Skip a UTF-8 byte order mark.
+			if bom, err := r.Peek(3); err == nil && string(bom) == "\uFEFF" {
+				r.Discard(3)
+				continue
+			}
+			return SourceLastFM
+		case '[', '{':
+			return SourceListenBrainz
+		default:
+			return SourceLastFM
+		}
+	}
+}
+
+// ReadLastFMScrobbles reads a Last.fm scrobble CSV export and calls add for
+// every listen. Exports with a header row are read by column name (artist,
+// album and track or title); headerless exports are taken to have the
+// common artist, album, title, date layout.
+func ReadLastFMScrobbles(r io.Reader, add func(Track)) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	artist, album, title := 0, 1, 2
+	first := true
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read Last.fm CSV: %w", err)
+		}
+
+		if first {
+			first = false
+			if len(record) > 0 {
+				record[0] = strings.TrimPrefix(record[0], "\uFEFF")
+			}
+			if index := columnIndex(record, "artist"); index != -1 {
+				artist, album, title = index, columnIndex(record, "album"), columnIndex(record, "track")
+				if title == -1 {
+					title = columnIndex(record, "title")
+				}
+				if title == -1 {
+					return errors.New("the Last.fm CSV has no track column")
+				}
+				continue
+			}
+		}
+
+		track := Track{Title: cell(record, title), Album: cell(record, album)}
+		if name := cell(record, artist); name != "" {
+			track.Artists = []string{name}
+		}
+		add(track)
+	}
+}
+
+type listenBrainzListen struct {
+	TrackMetadata struct {
+		ArtistName     string `json:"artist_name"`
+		TrackName      string `json:"track_name"`
+		ReleaseName    string `json:"release_name"`
+		AdditionalInfo struct {
+			DurationMs int `json:"duration_ms"`
+			Duration   int `json:"duration"`
+		} `json:"additional_info"`
+	} `json:"track_metadata"`
+}
+
+func (listen *listenBrainzListen) track() Track {
+	metadata := listen.TrackMetadata
+	track := Track{
+		Title: strings.TrimSpace(metadata.TrackName),
+		Album: strings.TrimSpace(metadata.ReleaseName),
+	}
+	if artist := strings.TrimSpace(metadata.ArtistName); artist != "" {
+		track.Artists = []string{artist}
+	}
+
+	switch info := metadata.AdditionalInfo; {
+	case info.DurationMs > 0:
+		track.Duration = (info.DurationMs + 500) / 1000
+	case info.Duration > 0:
+		track.Duration = info.Duration
+	}
+	return track
+}
+
+// ReadListenBrainzListens reads a ListenBrainz listen export and calls add
+// for every listen. Both the JSON array and the one-listen-per-line JSONL
+// formats are accepted, as is an API response with payload.listens.
+func ReadListenBrainzListens(r io.Reader, add func(Track)) error {
+	decoder := json.NewDecoder(r)
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read ListenBrainz JSON: %w", err)
+		}
+
+		switch token {
+		case json.Delim('['):
+			for decoder.More() {
+				var listen listenBrainzListen
+				if err := decoder.Decode(&listen); err != nil {
+					return fmt.Errorf("failed to read ListenBrainz JSON: %w", err)
+				}
+				add(listen.track())
+			}
+			if _, err := decoder.Token(); err != nil {
+				return fmt.Errorf("failed to read ListenBrainz JSON: %w", err)
+			}
+		case json.Delim('{'):
+			if err := readListenBrainzObject(decoder, add); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("failed to read ListenBrainz JSON: unexpected %v", token)
+		}
+	}
+}
+
+// readListenBrainzObject reads the members of an object whose opening
+// brace has been consumed: either a single listen or an API response.
+func readListenBrainzObject(decoder *json.Decoder, add func(Track)) error {
+	var listen listenBrainzListen
+	var payload struct {
+		Listens []listenBrainzListen `json:"listens"`
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("failed to read ListenBrainz JSON: %w", err)
+		}
+
+		switch key {
+		case "track_metadata":
+			err = decoder.Decode(&listen.TrackMetadata)
+		case "payload":
+			err = decoder.Decode(&payload)
+		default:
+			var skip json.RawMessage
+			err = decoder.Decode(&skip)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read ListenBrainz JSON: %w", err)
+		}
+	}
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("failed to read ListenBrainz JSON: %w", err)
+	}
+
+	if len(payload.Listens) > 0 {
+		for _, listen := range payload.Listens {
+			add(listen.track())
+		}
+		return nil
+	}
+	add(listen.track())
+	return nil
+}
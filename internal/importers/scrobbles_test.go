@@ -0,0 +1,111 @@
+package importers
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlayCounter(t *testing.T) {
+	counter := NewPlayCounter()
+	for _, track := range []Track{
+		{Title: "Tattoo", Artists: []string{"Loreen"}},
+		{Title: "Euphoria", Artists: []string{"Loreen"}},
+		{Title: "EUPHORIA", Artists: []string{"loreen"}},
+		{Title: "Euphoria (Radio Edit)", Artists: []string{"Loreen"}},
+		{Title: "Hello", Artists: []string{"Adele"}},
+		{Title: "Tattoo", Artists: []string{"Loreen"}},
+		{Title: ""},
+	} {
+		counter.Add(track)
+	}
+
+	if counter.Listens() != 6 || counter.Tracks() != 3 {
+		t.Errorf("Expected 6 listens of 3 tracks, got %d of %d", counter.Listens(), counter.Tracks())
+	}
+
+	expected := []PlayCount{
+		{Track: Track{Title: "Euphoria", Artists: []string{"Loreen"}}, Plays: 3},
+		{Track: Track{Title: "Tattoo", Artists: []string{"Loreen"}}, Plays: 2},
+	}
+	if top := counter.Top(2); !reflect.DeepEqual(top, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, top)
+	}
+	if top := counter.Top(10); len(top) != 3 {
+		t.Errorf("Expected every track when n is larger, got %d", len(top))
+	}
+}
+
+func TestDetectScrobbleSource(t *testing.T) {
+	tests := map[string]string{
+		"Loreen,Heal,Euphoria,31 Jan 2024 12:00": SourceLastFM,
+		"  \n[{\"track_metadata\":{}}]":          SourceListenBrainz,
+		"{\"listened_at\":1}\n":                  SourceListenBrainz,
+		"\uFEFF[]":                               SourceListenBrainz,
+		"":                                       SourceLastFM,
+	}
+
+	for data, expected := range tests {
+		if source := DetectScrobbleSource(bufio.NewReader(strings.NewReader(data))); source != expected {
+			t.Errorf("DetectScrobbleSource(%q) = %q; want %q", data, source, expected)
+		}
+	}
+}
+
+func collect(t *testing.T, read func(add func(Track)) error) []Track {
+	t.Helper()
+
+	var tracks []Track
+	if err := read(func(track Track) { tracks = append(tracks, track) }); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return tracks
+}
+
+func TestReadLastFMScrobbles(t *testing.T) {
+	expected := []Track{
+		{Title: "Euphoria", Artists: []string{"Loreen"}, Album: "Heal"},
+		{Title: "Hello", Artists: []string{"Adele"}, Album: "25"},
+	}
+
+	headerless := "Loreen,Heal,Euphoria,31 Jan 2024 12:00\nAdele,25,Hello,30 Jan 2024 08:15\n"
+	tracks := collect(t, func(add func(Track)) error {
+		return ReadLastFMScrobbles(strings.NewReader(headerless), add)
+	})
+	if !reflect.DeepEqual(tracks, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, tracks)
+	}
+
+	withHeader := "uts,utc_time,artist,artist_mbid,album,album_mbid,track,track_mbid\n" +
+		"1706702400,31 Jan 2024 12:00,Loreen,,Heal,,Euphoria,\n" +
+		"1706602500,30 Jan 2024 08:15,Adele,,25,,Hello,\n"
+	tracks = collect(t, func(add func(Track)) error {
+		return ReadLastFMScrobbles(strings.NewReader(withHeader), add)
+	})
+	if !reflect.DeepEqual(tracks, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, tracks)
+	}
+}
+
+func TestReadListenBrainzListens(t *testing.T) {
+	listen := `{"listened_at":1706702400,"track_metadata":{"artist_name":"Loreen","track_name":"Euphoria","release_name":"Heal","additional_info":{"duration_ms":184320}}}`
+	expected := Track{Title: "Euphoria", Artists: []string{"Loreen"}, Album: "Heal", Duration: 184}
+
+	for name, data := range map[string]string{
+		"array":   "[" + listen + "," + listen + "]",
+		"jsonl":   listen + "\n" + listen + "\n",
+		"payload": `{"payload":{"count":2,"listens":[` + listen + "," + listen + `]}}`,
+	} {
+		tracks := collect(t, func(add func(Track)) error {
+			return ReadListenBrainzListens(strings.NewReader(data), add)
+		})
+		if len(tracks) != 2 || !reflect.DeepEqual(tracks[0], expected) {
+			t.Errorf("%s: expected two listens of %+v, got %+v", name, expected, tracks)
+		}
+	}
+
+	if err := ReadListenBrainzListens(strings.NewReader(`[{"track_metadata":`), func(Track) {}); err == nil {
+		t.Error("Expected an error for truncated JSON")
+	}
+}
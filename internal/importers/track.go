@@ -0,0 +1,76 @@
+// Package importers parses playlists and libraries exported by other music
+// players into tracks that can be searched for on YouTube.
+package importers
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Track is a single entry of an imported playlist or library.
+type Track struct {
+	Title    string   `json:"title"`
+	Artists  []string `json:"artists,omitempty"`
+	Album    string   `json:"album,omitempty"`
+	Duration int      `json:"duration,omitempty"` // seconds, 0 when unknown
+}
+
+// ErrUnknownFormat is returned by ParsePlaylist when the format can't be
+// detected from the file name or contents.
+var ErrUnknownFormat = errors.New("unknown playlist format")
+
+// trackNumberPrefix matches the "01 - ", "1. " or zero-padded "01 " that
+// file names often start with. A number followed only by a space, as in
+// "99 Luftballons", is part of the title.
+var trackNumberPrefix = regexp.MustCompile(`^(?:\d{1,3}\s*[-.]\s*|0\d{1,2}\s+)`)
+
+// splitArtistTitle splits the common "Artist - Title" form. Without a
+// separator, the whole string is the title.
+func splitArtistTitle(s string) Track {
+	s = strings.TrimSpace(s)
+	if artist, title, found := strings.Cut(s, " - "); found && strings.TrimSpace(artist) != "" && strings.TrimSpace(title) != "" {
+		return Track{Title: strings.TrimSpace(title), Artists: []string{strings.TrimSpace(artist)}}
+	}
+	return Track{Title: s}
+}
+
+// trackFromLocation derives a track from a file path or URL such as
+// "Music/01 - Loreen - Euphoria.mp3". URLs are percent-decoded.
+func trackFromLocation(location string) Track {
+	location = strings.ReplaceAll(strings.TrimSpace(location), `\`, "/")
+	if u, err := url.Parse(location); err == nil && u.Scheme != "" && len(u.Scheme) > 1 {
+		location = u.Path
+	}
+	name := path.Base(location)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	name = trackNumberPrefix.ReplaceAllString(name, "")
+	return splitArtistTitle(strings.ReplaceAll(name, "_", " "))
+}
+
+// decodeText returns data as a string, treating it as Windows-1252 when it
+// isn't valid UTF-8, which is what older players write .m3u and .pls files
+// in.
+func decodeText(data []byte) string {
+	data = trimBOM(data)
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	decoded, err := charmap.Windows1252.NewDecoder().Bytes(data)
+	if err != nil {
+		return string(data)
+	}
+	return string(decoded)
+}
+
+func trimBOM(data []byte) []byte {
+	if len(data) >= 3 && data[0] == 0xEF && data[1] == 0xBB && data[2] == 0xBF {
+		return data[3:]
+	}
+	return data
+}
@@ -0,0 +1,51 @@
+package importers
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type xspfPlaylist struct {
+	Tracks []struct {
+		Location string `xml:"location"`
+		Title    string `xml:"title"`
+		Creator  string `xml:"creator"`
+		Album    string `xml:"album"`
+		Duration int    `xml:"duration"` // milliseconds
+	} `xml:"trackList>track"`
+}
+
+// ParseXSPF parses an XSPF playlist. Tracks without a title are named after
+// their location.
+func ParseXSPF(data []byte) ([]Track, error) {
+	var playlist xspfPlaylist
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&playlist); err != nil {
+		return nil, fmt.Errorf("failed to parse XSPF: %w", err)
+	}
+
+	tracks := make([]Track, 0, len(playlist.Tracks))
+	for _, entry := range playlist.Tracks {
+		track := Track{
+			Title:    strings.TrimSpace(entry.Title),
+			Album:    strings.TrimSpace(entry.Album),
+			Duration: (entry.Duration + 500) / 1000,
+		}
+		if creator := strings.TrimSpace(entry.Creator); creator != "" {
+			track.Artists = []string{creator}
+		}
+
+		if track.Title == "" {
+			fromLocation := trackFromLocation(entry.Location)
+			track.Title = fromLocation.Title
+			if len(track.Artists) == 0 {
+				track.Artists = fromLocation.Artists
+			}
+		}
+
+		tracks = append(tracks, track)
+	}
+
+	return tracks, nil
+}
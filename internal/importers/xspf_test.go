@@ -0,0 +1,43 @@
+package importers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseXSPF(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<playlist version="1" xmlns="http://xspf.org/ns/0/">
+  <trackList>
+    <track>
+      <location>file:///music/euphoria.mp3</location>
+      <title>Euphoria</title>
+      <creator>Loreen</creator>
+      <album>Heal</album>
+      <duration>184320</duration>
+    </track>
+    <track>
+      <location>file:///music/Kino%20-%20Gruppa%20Krovi.mp3</location>
+    </track>
+  </trackList>
+</playlist>`)
+
+	tracks, err := ParseXSPF(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []Track{
+		{Title: "Euphoria", Artists: []string{"Loreen"}, Album: "Heal", Duration: 184},
+		{Title: "Gruppa Krovi", Artists: []string{"Kino"}},
+	}
+	if !reflect.DeepEqual(tracks, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, tracks)
+	}
+}
+
+func TestParseXSPF_Invalid(t *testing.T) {
+	if _, err := ParseXSPF([]byte("<playlist><trackList>")); err == nil {
+		t.Error("Expected an error for truncated XML")
+	}
+}
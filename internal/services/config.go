@@ -0,0 +1,278 @@
+package services
+
+import (
+	"log"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultBaseURL          = "https://www.youtube.com"
+	defaultUserAgent        = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	defaultMaxProxyFailures = 3
+	defaultProxyRetryAfter  = 5 * time.Minute
+
+	defaultDurationTolerance = 15 * time.Second
+
+	defaultThumbnailBaseURL   = "https://i.ytimg.com"
+	defaultThumbnailCacheSize = 100 << 20 // bytes
+)
+
+// Config controls how the service talks to YouTube.
+type Config struct {
+	BaseURL string
+
+	// MusicBaseURL is the YouTube Music server searched when SearchSource,
+	// or a search's own source, is SourceMusic.
+	MusicBaseURL string
+	SearchSource string
+
+	// Proxies are http://, https:// or socks5:// URLs. When empty, requests
+	// are made directly. A proxy that fails MaxProxyFailures times in a row
+	// is retired for ProxyRetryAfter.
+	Proxies          []string
+	ProxyStrategy    ProxyStrategy
+	MaxProxyFailures int
+	ProxyRetryAfter  time.Duration
+
+	// UserAgents are rotated round-robin, one per outbound request.
+	UserAgents     []string
+	AcceptLanguage string
+	Headers        map[string]string
+
+	// Language and Region are sent as YouTube's hl and gl parameters.
+	Language string
+	Region   string
+
+	Timeout time.Duration
+
+	// DurationTolerance is how far a candidate's length may differ from
+	// the requested duration before it is penalized. nil uses 15 seconds;
+	// 0 only accepts exact lengths.
+	DurationTolerance *time.Duration
+
+	// MinConfidence is the default minimum confidence for a candidate to be
+	// returned. 0 returns the best candidate however weak it is.
+	MinConfidence float64
+
+	// MusicBrainzBaseURL is the MusicBrainz-compatible server used to look
+	// up ISRCs and recording IDs.
+	MusicBrainzBaseURL string
+
+	// Spotify track lookups go to SpotifyAPIBaseURL. When SpotifyClientID
+	// is set, tokens are requested from SpotifyAccountsBaseURL with the
+	// client credentials flow.
+	SpotifyAPIBaseURL      string
+	SpotifyAccountsBaseURL string
+	SpotifyClientID        string
+	SpotifyClientSecret    string
+
+	// WatchFallbackURL is where /watch sends clients when no video is found,
+	// with {query} replaced by the URL-encoded search query. When empty,
+	// they are sent to the YouTube search results for the song.
+	WatchFallbackURL string
+
+	// Thumbnails are fetched from ThumbnailBaseURL and kept in
+	// ThumbnailCacheDir until they take up more than ThumbnailCacheSize
+	// bytes. A size of 0 disables the cache.
+	ThumbnailBaseURL   string
+	ThumbnailCacheDir  string
+	ThumbnailCacheSize int64
+}
+
+func DefaultConfig() Config {
+	return Config{
+		BaseURL:                defaultBaseURL,
+		MusicBaseURL:           defaultMusicBaseURL,
+		SearchSource:           SourceYouTube,
+		ProxyStrategy:          ProxyRoundRobin,
+		MaxProxyFailures:       defaultMaxProxyFailures,
+		ProxyRetryAfter:        defaultProxyRetryAfter,
+		UserAgents:             []string{defaultUserAgent},
+		MusicBrainzBaseURL:     defaultMusicBrainzBaseURL,
+		SpotifyAPIBaseURL:      defaultSpotifyAPIBaseURL,
+		SpotifyAccountsBaseURL: defaultSpotifyAccountsBaseURL,
+		ThumbnailBaseURL:       defaultThumbnailBaseURL,
+		ThumbnailCacheDir:      filepath.Join(os.TempDir(), "youtube-music-video-api", "thumbnails"),
+		ThumbnailCacheSize:     defaultThumbnailCacheSize,
+	}
+}
+
+// ConfigFromEnv starts from DefaultConfig and applies any of the following
+// environment variables that are set:
+//
+//	YOUTUBE_BASE_URL          base URL for youtube.com requests
+//	YOUTUBE_MUSIC_BASE_URL    base URL for music.youtube.com requests
+//	SEARCH_SOURCE             default search source, youtube or music
+//	PROXY_URLS                comma-separated proxy URLs
+//	PROXY_STRATEGY            round-robin or least-failures
+//	PROXY_MAX_FAILURES        consecutive failures before a proxy is retired
+//	PROXY_RETRY_AFTER         how long a retired proxy rests, e.g. 5m
+//	USER_AGENTS               |-separated list of user agents
+//	ACCEPT_LANGUAGE           Accept-Language header value
+//	OUTBOUND_HEADERS          |-separated list of "Name: value" headers
+//	YOUTUBE_HL                default interface language (hl)
+//	YOUTUBE_GL                default region (gl)
+//	OUTBOUND_TIMEOUT          request timeout, e.g. 10s
+//	DURATION_TOLERANCE        default duration matching tolerance, e.g. 15s
+//	MIN_CONFIDENCE            default minimum confidence between 0 and 1
+//	MUSICBRAINZ_BASE_URL      base URL of the MusicBrainz-compatible metadata server
+//	SPOTIFY_API_BASE_URL      base URL of the Spotify Web API
+//	SPOTIFY_ACCOUNTS_BASE_URL base URL of the Spotify accounts service
+//	SPOTIFY_CLIENT_ID         client ID for the client credentials flow
+//	SPOTIFY_CLIENT_SECRET     client secret for the client credentials flow
+//	WATCH_FALLBACK_URL        page /watch redirects to when nothing is found
+//	THUMBNAIL_BASE_URL        base URL for thumbnail images
+//	THUMBNAIL_CACHE_DIR       directory thumbnails are cached in
+//	THUMBNAIL_CACHE_MB        thumbnail cache size in megabytes, 0 to disable
+func ConfigFromEnv() Config {
+	config := DefaultConfig()
+
+	if value := os.Getenv("YOUTUBE_BASE_URL"); value != "" {
+		config.BaseURL = strings.TrimRight(value, "/")
+	}
+
+	if value := os.Getenv("YOUTUBE_MUSIC_BASE_URL"); value != "" {
+		config.MusicBaseURL = strings.TrimRight(value, "/")
+	}
+
+	if value := os.Getenv("SEARCH_SOURCE"); value != "" {
+		if value == SourceYouTube || value == SourceMusic {
+			config.SearchSource = value
+		} else {
+			log.Printf("Ignoring unknown SEARCH_SOURCE %q", value)
+		}
+	}
+
+	if value := os.Getenv("PROXY_URLS"); value != "" {
+		config.Proxies = splitList(value, ",")
+	}
+
+	if value := os.Getenv("PROXY_STRATEGY"); value != "" {
+		strategy := ProxyStrategy(value)
+		if strategy.Valid() {
+			config.ProxyStrategy = strategy
+		} else {
+			log.Printf("Ignoring unknown PROXY_STRATEGY %q", value)
+		}
+	}
+
+	if value := os.Getenv("PROXY_MAX_FAILURES"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n > 0 {
+			config.MaxProxyFailures = n
+		} else {
+			log.Printf("Ignoring invalid PROXY_MAX_FAILURES %q", value)
+		}
+	}
+
+	if value := os.Getenv("PROXY_RETRY_AFTER"); value != "" {
+		if retryAfter, err := time.ParseDuration(value); err == nil && retryAfter > 0 {
+			config.ProxyRetryAfter = retryAfter
+		} else {
+			log.Printf("Ignoring invalid PROXY_RETRY_AFTER %q", value)
+		}
+	}
+
+	if value := os.Getenv("USER_AGENTS"); value != "" {
+		if agents := splitList(value, "|"); len(agents) > 0 {
+			config.UserAgents = agents
+		}
+	}
+
+	config.AcceptLanguage = os.Getenv("ACCEPT_LANGUAGE")
+	config.Language = os.Getenv("YOUTUBE_HL")
+	config.Region = os.Getenv("YOUTUBE_GL")
+
+	if value := os.Getenv("OUTBOUND_HEADERS"); value != "" {
+		config.Headers = make(map[string]string)
+		for _, header := range splitList(value, "|") {
+			name, headerValue, ok := strings.Cut(header, ":")
+			if !ok || strings.TrimSpace(name) == "" {
+				log.Printf("Ignoring malformed OUTBOUND_HEADERS entry %q", header)
+				continue
+			}
+			config.Headers[strings.TrimSpace(name)] = strings.TrimSpace(headerValue)
+		}
+	}
+
+	if value := os.Getenv("OUTBOUND_TIMEOUT"); value != "" {
+		if timeout, err := time.ParseDuration(value); err == nil {
+			config.Timeout = timeout
+		} else {
+			log.Printf("Ignoring invalid OUTBOUND_TIMEOUT %q", value)
+		}
+	}
+
+	if value := os.Getenv("DURATION_TOLERANCE"); value != "" {
+		if tolerance, err := time.ParseDuration(value); err == nil && tolerance >= 0 {
+			config.DurationTolerance = &tolerance
+		} else {
+			log.Printf("Ignoring invalid DURATION_TOLERANCE %q", value)
+		}
+	}
+
+	if value := os.Getenv("MUSICBRAINZ_BASE_URL"); value != "" {
+		config.MusicBrainzBaseURL = strings.TrimRight(value, "/")
+	}
+
+	if value := os.Getenv("SPOTIFY_API_BASE_URL"); value != "" {
+		config.SpotifyAPIBaseURL = strings.TrimRight(value, "/")
+	}
+
+	if value := os.Getenv("SPOTIFY_ACCOUNTS_BASE_URL"); value != "" {
+		config.SpotifyAccountsBaseURL = strings.TrimRight(value, "/")
+	}
+
+	config.SpotifyClientID = os.Getenv("SPOTIFY_CLIENT_ID")
+	config.SpotifyClientSecret = os.Getenv("SPOTIFY_CLIENT_SECRET")
+
+	if value := os.Getenv("MIN_CONFIDENCE"); value != "" {
+		if confidence, err := strconv.ParseFloat(value, 64); err == nil && !math.IsNaN(confidence) && confidence >= 0 && confidence <= 1 {
+			config.MinConfidence = confidence
+		} else {
+			log.Printf("Ignoring invalid MIN_CONFIDENCE %q", value)
+		}
+	}
+
+	if value := os.Getenv("WATCH_FALLBACK_URL"); value != "" {
+		if u, err := url.Parse(value); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+			config.WatchFallbackURL = value
+		} else {
+			log.Printf("Ignoring invalid WATCH_FALLBACK_URL %q", value)
+		}
+	}
+
+	if value := os.Getenv("THUMBNAIL_BASE_URL"); value != "" {
+		config.ThumbnailBaseURL = strings.TrimRight(value, "/")
+	}
+
+	if value := os.Getenv("THUMBNAIL_CACHE_DIR"); value != "" {
+		config.ThumbnailCacheDir = value
+	}
+
+	if value := os.Getenv("THUMBNAIL_CACHE_MB"); value != "" {
+		if megabytes, err := strconv.ParseInt(value, 10, 64); err == nil && megabytes >= 0 {
+			config.ThumbnailCacheSize = megabytes << 20
+		} else {
+			log.Printf("Ignoring invalid THUMBNAIL_CACHE_MB %q", value)
+		}
+	}
+
+	return config
+}
+
+func splitList(value, separator string) []string {
+	var items []string
+	for _, item := range strings.Split(value, separator) {
+		trimmed := strings.TrimSpace(item)
+		if trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
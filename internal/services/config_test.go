@@ -0,0 +1,138 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv_Defaults(t *testing.T) {
+	config := ConfigFromEnv()
+
+	if config.BaseURL != defaultBaseURL {
+		t.Errorf("Expected base URL %q, got %q", defaultBaseURL, config.BaseURL)
+	}
+	if len(config.UserAgents) != 1 || config.UserAgents[0] != defaultUserAgent {
+		t.Errorf("Expected default user agent, got %v", config.UserAgents)
+	}
+	if config.ProxyStrategy != ProxyRoundRobin {
+		t.Errorf("Expected round-robin strategy, got %q", config.ProxyStrategy)
+	}
+	if len(config.Proxies) != 0 {
+		t.Errorf("Expected no proxies, got %v", config.Proxies)
+	}
+}
+
+func TestConfigFromEnv_Overrides(t *testing.T) {
+	t.Setenv("YOUTUBE_BASE_URL", "http://localhost:8080/")
+	t.Setenv("PROXY_URLS", "http://a:8080, socks5://b:1080")
+	t.Setenv("PROXY_STRATEGY", "least-failures")
+	t.Setenv("PROXY_MAX_FAILURES", "5")
+	t.Setenv("PROXY_RETRY_AFTER", "10m")
+	t.Setenv("USER_AGENTS", "agent-one | agent-two")
+	t.Setenv("ACCEPT_LANGUAGE", "sv-SE,sv;q=0.9")
+	t.Setenv("OUTBOUND_HEADERS", "X-One: 1|X-Two:two")
+	t.Setenv("YOUTUBE_HL", "sv")
+	t.Setenv("YOUTUBE_GL", "SE")
+	t.Setenv("OUTBOUND_TIMEOUT", "5s")
+	t.Setenv("DURATION_TOLERANCE", "30s")
+	t.Setenv("MIN_CONFIDENCE", "0.6")
+	t.Setenv("WATCH_FALLBACK_URL", "https://example.com/not-found?q={query}")
+	t.Setenv("THUMBNAIL_CACHE_DIR", "/var/cache/thumbnails")
+	t.Setenv("THUMBNAIL_CACHE_MB", "0")
+
+	config := ConfigFromEnv()
+
+	if config.BaseURL != "http://localhost:8080" {
+		t.Errorf("Expected trailing slash to be trimmed, got %q", config.BaseURL)
+	}
+	if len(config.Proxies) != 2 || config.Proxies[1] != "socks5://b:1080" {
+		t.Errorf("Unexpected proxies %v", config.Proxies)
+	}
+	if config.ProxyStrategy != ProxyLeastFailures {
+		t.Errorf("Expected least-failures, got %q", config.ProxyStrategy)
+	}
+	if config.MaxProxyFailures != 5 {
+		t.Errorf("Expected 5 max failures, got %d", config.MaxProxyFailures)
+	}
+	if config.ProxyRetryAfter != 10*time.Minute {
+		t.Errorf("Expected proxies to be retried after 10m, got %v", config.ProxyRetryAfter)
+	}
+	if len(config.UserAgents) != 2 || config.UserAgents[1] != "agent-two" {
+		t.Errorf("Unexpected user agents %v", config.UserAgents)
+	}
+	if config.AcceptLanguage != "sv-SE,sv;q=0.9" {
+		t.Errorf("Unexpected Accept-Language %q", config.AcceptLanguage)
+	}
+	if config.Headers["X-One"] != "1" || config.Headers["X-Two"] != "two" {
+		t.Errorf("Unexpected headers %v", config.Headers)
+	}
+	if config.Language != "sv" || config.Region != "SE" {
+		t.Errorf("Unexpected hl/gl %q/%q", config.Language, config.Region)
+	}
+	if config.Timeout != 5*time.Second {
+		t.Errorf("Expected 5s timeout, got %v", config.Timeout)
+	}
+	if config.DurationTolerance == nil || *config.DurationTolerance != 30*time.Second {
+		t.Errorf("Expected 30s duration tolerance, got %v", config.DurationTolerance)
+	}
+	if config.MinConfidence != 0.6 {
+		t.Errorf("Expected 0.6 min confidence, got %v", config.MinConfidence)
+	}
+	if config.WatchFallbackURL != "https://example.com/not-found?q={query}" {
+		t.Errorf("Unexpected watch fallback URL %q", config.WatchFallbackURL)
+	}
+	if config.ThumbnailCacheDir != "/var/cache/thumbnails" || config.ThumbnailCacheSize != 0 {
+		t.Errorf("Unexpected thumbnail cache %q of %d bytes", config.ThumbnailCacheDir, config.ThumbnailCacheSize)
+	}
+}
+
+func TestConfigFromEnv_ZeroDurationTolerance(t *testing.T) {
+	t.Setenv("DURATION_TOLERANCE", "0s")
+
+	config := ConfigFromEnv()
+	if config.DurationTolerance == nil || *config.DurationTolerance != 0 {
+		t.Fatalf("Expected a tolerance of 0, got %v", config.DurationTolerance)
+	}
+
+	ys, err := NewYouTubeServiceWithConfig(config)
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+	if tolerance := ys.withDefaults(SearchOptions{}).DurationTolerance; *tolerance != 0 {
+		t.Errorf("Expected searches to default to a tolerance of 0, got %v", *tolerance)
+	}
+}
+
+func TestConfigFromEnv_MinConfidenceNaN(t *testing.T) {
+	t.Setenv("MIN_CONFIDENCE", "NaN")
+
+	if config := ConfigFromEnv(); config.MinConfidence != 0 {
+		t.Errorf("Expected min confidence to stay disabled, got %v", config.MinConfidence)
+	}
+}
+
+func TestConfigFromEnv_InvalidValuesFallBack(t *testing.T) {
+	t.Setenv("PROXY_STRATEGY", "random")
+	t.Setenv("PROXY_MAX_FAILURES", "-1")
+	t.Setenv("OUTBOUND_TIMEOUT", "soon")
+	t.Setenv("MIN_CONFIDENCE", "2")
+	t.Setenv("WATCH_FALLBACK_URL", "javascript:alert(1)")
+
+	config := ConfigFromEnv()
+
+	if config.ProxyStrategy != ProxyRoundRobin {
+		t.Errorf("Expected default strategy, got %q", config.ProxyStrategy)
+	}
+	if config.MaxProxyFailures != defaultMaxProxyFailures {
+		t.Errorf("Expected default max failures, got %d", config.MaxProxyFailures)
+	}
+	if config.Timeout != 0 {
+		t.Errorf("Expected no timeout, got %v", config.Timeout)
+	}
+	if config.MinConfidence != 0 {
+		t.Errorf("Expected min confidence to stay disabled, got %v", config.MinConfidence)
+	}
+	if config.WatchFallbackURL != "" {
+		t.Errorf("Expected no watch fallback URL, got %q", config.WatchFallbackURL)
+	}
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// featureSeparator splits "A feat. B" into the primary and featured parts.
+	featureSeparator = regexp.MustCompile(`(?i)\s+(?:feat\.?|ft\.?|featuring|with)\s+`)
+
+	// collaborationSeparator splits credits between equally billed artists.
+	// Commas and "&" are left alone because they are part of band names
+	// such as "Earth, Wind & Fire"; see listSeparator.
+	collaborationSeparator = regexp.MustCompile(`(?i)\s+x\s+|\s+vs\.?\s+`)
+
+	// listSeparator splits a primary credit such as "A & B" only when one of
+	// its parts is credited as featured elsewhere.
+	listSeparator = regexp.MustCompile(`\s*,\s*|\s+&\s+`)
+
+	// featuredListSeparator also accepts "and" because "feat. A and B"
+	// always lists separate artists.
+	featuredListSeparator = regexp.MustCompile(`(?i)\s*,\s*|\s+&\s+|\s+and\s+|\s+x\s+`)
+
+	titleFeaturePattern = regexp.MustCompile(`(?i)\s*[\(\[]\s*(?:feat\.?|ft\.?|featuring|with)\s+([^\)\]]+)[\)\]]|\s+(?:feat\.?|ft\.?|featuring)\s+(.+)$`)
+)
+
+// Credits is the structured breakdown of who performs a song.
+type Credits struct {
+	Title    string   `json:"title"`
+	Primary  []string `json:"primary"`
+	Featured []string `json:"featured,omitempty"`
+}
+
+// ParseCredits splits the artists credited in title and artists into primary
+// and featured artists. Title is returned without its featuring clause.
+func ParseCredits(title string, artists []string) Credits {
+	credits := Credits{Title: strings.TrimSpace(title)}
+
+	var featured []string
+	for _, match := range titleFeaturePattern.FindAllStringSubmatch(credits.Title, -1) {
+		names := match[1]
+		if names == "" {
+			names = match[2]
+		}
+		featured = append(featured, splitArtists(names, featuredListSeparator)...)
+	}
+	if len(featured) > 0 {
+		credits.Title = strings.TrimSpace(titleFeaturePattern.ReplaceAllString(credits.Title, ""))
+	}
+
+	var primary []string
+	for _, artist := range artists {
+		parts := featureSeparator.Split(artist, 2)
+		primary = append(primary, splitArtists(parts[0], collaborationSeparator)...)
+		if len(parts) == 2 {
+			featured = append(featured, splitArtists(parts[1], featuredListSeparator)...)
+		}
+	}
+
+	featured = uniqueArtists(featured)
+
+	// An artist the title credits as featured is featured, even when the
+	// artists field lists them alongside the primary artist.
+	isFeatured := make(map[string]bool)
+	for _, artist := range featured {
+		isFeatured[NormalizeText(artist)] = true
+	}
+	primary = splitFeaturedListings(primary, isFeatured)
+
+	var remaining []string
+	for _, artist := range uniqueArtists(primary) {
+		if !isFeatured[NormalizeText(artist)] {
+			remaining = append(remaining, artist)
+		}
+	}
+
+	if len(remaining) == 0 && len(featured) > 0 && len(primary) > 0 {
+		// Never demote everyone; fall back to the artists as given.
+		remaining = uniqueArtists(primary)
+		featured = withoutArtists(featured, remaining)
+	}
+
+	credits.Primary = remaining
+	credits.Featured = featured
+	if credits.Primary == nil {
+		credits.Primary = []string{}
+	}
+	return credits
+}
+
+// Artists returns the primary artists followed by the featured ones.
+func (c Credits) Artists() []string {
+	artists := make([]string, 0, len(c.Primary)+len(c.Featured))
+	artists = append(artists, c.Primary...)
+	return append(artists, c.Featured...)
+}
+
+func splitArtists(s string, separator *regexp.Regexp) []string {
+	var artists []string
+	for _, artist := range separator.Split(s, -1) {
+		if trimmed := strings.TrimSpace(artist); trimmed != "" {
+			artists = append(artists, trimmed)
+		}
+	}
+	return artists
+}
+
+// splitFeaturedListings splits credits such as "A & B" into their parts
+// when one of them is featured, and keeps every other credit whole.
+func splitFeaturedListings(artists []string, isFeatured map[string]bool) []string {
+	var split []string
+	for _, artist := range artists {
+		parts := splitArtists(artist, listSeparator)
+		if slices.ContainsFunc(parts, func(part string) bool { return isFeatured[NormalizeText(part)] }) {
+			split = append(split, parts...)
+		} else {
+			split = append(split, artist)
+		}
+	}
+	return split
+}
+
+func uniqueArtists(artists []string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, artist := range artists {
+		key := NormalizeText(artist)
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, artist)
+		}
+	}
+	return unique
+}
+
+func withoutArtists(artists, excluded []string) []string {
+	skip := make(map[string]bool)
+	for _, artist := range excluded {
+		skip[NormalizeText(artist)] = true
+	}
+	var kept []string
+	for _, artist := range artists {
+		if !skip[NormalizeText(artist)] {
+			kept = append(kept, artist)
+		}
+	}
+	return kept
+}
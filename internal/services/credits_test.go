@@ -0,0 +1,98 @@
+package services
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseCredits(t *testing.T) {
+	tests := []struct {
+		name     string
+		title    string
+		artists  []string
+		expected Credits
+	}{
+		{
+			name:     "Single artist",
+			title:    "Euphoria",
+			artists:  []string{"Loreen"},
+			expected: Credits{Title: "Euphoria", Primary: []string{"Loreen"}},
+		},
+		{
+			name:     "Featured in title and listed in artists",
+			title:    "Stay (with Justin Bieber)",
+			artists:  []string{"The Kid LAROI & Justin Bieber"},
+			expected: Credits{Title: "Stay", Primary: []string{"The Kid LAROI"}, Featured: []string{"Justin Bieber"}},
+		},
+		{
+			name:     "Featured in artists",
+			title:    "Lean On",
+			artists:  []string{"Major Lazer feat. MØ & DJ Snake"},
+			expected: Credits{Title: "Lean On", Primary: []string{"Major Lazer"}, Featured: []string{"MØ", "DJ Snake"}},
+		},
+		{
+			name:     "Trailing ft. in title",
+			title:    "Song ft. A and B",
+			artists:  []string{"C"},
+			expected: Credits{Title: "Song", Primary: []string{"C"}, Featured: []string{"A", "B"}},
+		},
+		{
+			name:     "Collaboration separators",
+			title:    "Track",
+			artists:  []string{"A x B", "C vs. D", "E"},
+			expected: Credits{Title: "Track", Primary: []string{"A", "B", "C", "D", "E"}},
+		},
+		{
+			name:     "Bracketed feat",
+			title:    "Song [feat. A, B]",
+			artists:  nil,
+			expected: Credits{Title: "Song", Primary: []string{}, Featured: []string{"A", "B"}},
+		},
+		{
+			name:     "Only artist is credited as featured",
+			title:    "Song (feat. A)",
+			artists:  []string{"A"},
+			expected: Credits{Title: "Song", Primary: []string{"A"}},
+		},
+		{
+			name:     "Band names with ampersands and commas",
+			title:    "Song",
+			artists:  []string{"Simon & Garfunkel", "Earth, Wind & Fire", "Crosby, Stills, Nash & Young"},
+			expected: Credits{Title: "Song", Primary: []string{"Simon & Garfunkel", "Earth, Wind & Fire", "Crosby, Stills, Nash & Young"}},
+		},
+		{
+			name:     "Band featuring another artist",
+			title:    "Boogie Wonderland",
+			artists:  []string{"Earth, Wind & Fire feat. The Emotions"},
+			expected: Credits{Title: "Boogie Wonderland", Primary: []string{"Earth, Wind & Fire"}, Featured: []string{"The Emotions"}},
+		},
+		{
+			name:     "Names containing x are not split",
+			title:    "Old Town Road",
+			artists:  []string{"Lil Nas X"},
+			expected: Credits{Title: "Old Town Road", Primary: []string{"Lil Nas X"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := ParseCredits(test.title, test.artists)
+			if result.Title != test.expected.Title {
+				t.Errorf("Expected title %q, got %q", test.expected.Title, result.Title)
+			}
+			if !slices.Equal(result.Primary, test.expected.Primary) {
+				t.Errorf("Expected primary %v, got %v", test.expected.Primary, result.Primary)
+			}
+			if !slices.Equal(result.Featured, test.expected.Featured) {
+				t.Errorf("Expected featured %v, got %v", test.expected.Featured, result.Featured)
+			}
+		})
+	}
+}
+
+func TestCredits_Artists(t *testing.T) {
+	credits := Credits{Primary: []string{"A"}, Featured: []string{"B", "C"}}
+	if result := credits.Artists(); !slices.Equal(result, []string{"A", "B", "C"}) {
+		t.Errorf("Expected [A B C], got %v", result)
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DiskCache keeps values as files in a directory and removes the least
+// recently used ones once together they take up more than maxBytes. Files
+// left by an earlier process are picked up, oldest first.
+type DiskCache struct {
+	dir      string
+	maxBytes int64
+	size     int64
+	files    map[string]*list.Element
+	list     *list.List
+	mutex    sync.Mutex
+}
+
+type diskEntry struct {
+	name string
+	size int64
+}
+
+func NewDiskCache(dir string, maxBytes int64) *DiskCache {
+	c := &DiskCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		files:    make(map[string]*list.Element),
+		list:     list.New(),
+	}
+
+	// The directory is created on the first Put, so a missing one just
+	// means an empty cache.
+	entries, _ := os.ReadDir(dir)
+	type existing struct {
+		diskEntry
+		modified time.Time
+	}
+	var found []existing
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || filepath.Ext(entry.Name()) != ".cache" {
+			continue
+		}
+		found = append(found, existing{diskEntry{entry.Name(), info.Size()}, info.ModTime()})
+	}
+	sort.Slice(found, func(i, j int) bool {
+		return found[i].modified.Before(found[j].modified)
+	})
+	for _, file := range found {
+		c.files[file.name] = c.list.PushFront(&diskEntry{file.name, file.size})
+		c.size += file.size
+	}
+
+	c.mutex.Lock()
+	c.evict()
+	c.mutex.Unlock()
+
+	return c
+}
+
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	name := diskCacheName(key)
+
+	c.mutex.Lock()
+	elem, exists := c.files[name]
+	if exists {
+		c.list.MoveToFront(elem)
+	}
+	c.mutex.Unlock()
+
+	if !exists {
+		return nil, false
+	}
+
+	path := filepath.Join(c.dir, name)
+	value, err := os.ReadFile(path)
+	if err != nil {
+		c.mutex.Lock()
+		c.remove(name)
+		c.mutex.Unlock()
+		return nil, false
+	}
+
+	// Record the use so the order survives a restart.
+	now := time.Now()
+	os.Chtimes(path, now, now)
+
+	return value, true
+}
+
+func (c *DiskCache) Put(key string, value []byte) {
+	size := int64(len(value))
+	// Don't store anything that could never fit
+	if size > c.maxBytes {
+		return
+	}
+
+	name := diskCacheName(key)
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+	// Write to a temporary file first so readers never see a partial value.
+	tmp, err := os.CreateTemp(c.dir, "put-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(value)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(c.dir, name))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if elem, exists := c.files[name]; exists {
+		c.size -= elem.Value.(*diskEntry).size
+		elem.Value.(*diskEntry).size = size
+		c.list.MoveToFront(elem)
+	} else {
+		c.files[name] = c.list.PushFront(&diskEntry{name, size})
+	}
+	c.size += size
+
+	c.evict()
+}
+
+// Size returns the total size of the cached values in bytes.
+func (c *DiskCache) Size() int64 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.size
+}
+
+// evict removes the least recently used files until the cache fits.
+// c.mutex must be held.
+func (c *DiskCache) evict() {
+	for c.size > c.maxBytes {
+		oldest := c.list.Back()
+		if oldest == nil {
+			return
+		}
+		name := oldest.Value.(*diskEntry).name
+		os.Remove(filepath.Join(c.dir, name))
+		c.remove(name)
+	}
+}
+
+// remove forgets the file. c.mutex must be held.
+func (c *DiskCache) remove(name string) {
+	if elem, exists := c.files[name]; exists {
+		c.size -= elem.Value.(*diskEntry).size
+		c.list.Remove(elem)
+		delete(c.files, name)
+	}
+}
+
+// diskCacheName maps key to a file name that is safe on any filesystem.
+func diskCacheName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:]) + ".cache"
+}
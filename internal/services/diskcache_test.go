@@ -0,0 +1,64 @@
+package services
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDiskCache(t *testing.T) {
+	cache := NewDiskCache(t.TempDir(), 10)
+
+	if _, found := cache.Get("a"); found {
+		t.Error("Expected a miss on an empty cache")
+	}
+
+	cache.Put("a", []byte("1234"))
+	cache.Put("b", []byte("5678"))
+
+	if value, found := cache.Get("a"); !found || string(value) != "1234" {
+		t.Errorf("Expected 1234, got %q (found %v)", value, found)
+	}
+
+	// b is now the least recently used and has to make room.
+	cache.Put("c", []byte("90"))
+	cache.Put("d", []byte("ab"))
+
+	if _, found := cache.Get("b"); found {
+		t.Error("Expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if _, found := cache.Get(key); !found {
+			t.Errorf("Expected %s to be cached", key)
+		}
+	}
+	if cache.Size() != 8 {
+		t.Errorf("Expected 8 cached bytes, got %d", cache.Size())
+	}
+}
+
+func TestDiskCache_SkipsOversizedValues(t *testing.T) {
+	cache := NewDiskCache(t.TempDir(), 4)
+
+	cache.Put("big", []byte(strings.Repeat("x", 5)))
+
+	if _, found := cache.Get("big"); found {
+		t.Error("Expected a value larger than the cache to be skipped")
+	}
+}
+
+func TestDiskCache_ReloadsExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	NewDiskCache(dir, 100).Put("a", []byte("1234"))
+
+	cache := NewDiskCache(dir, 100)
+	if value, found := cache.Get("a"); !found || string(value) != "1234" {
+		t.Errorf("Expected 1234 from disk, got %q (found %v)", value, found)
+	}
+
+	// A smaller limit evicts what no longer fits.
+	NewDiskCache(dir, 2)
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected the directory to be emptied, got %d files", len(entries))
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration parses a track length given either as whole seconds ("245")
+// or as a clock value ("4:05", "1:02:03").
+func ParseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	total := 0
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		if i > 0 && value >= 60 {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total = total*60 + value
+	}
+
+	return time.Duration(total) * time.Second, nil
+}
+
+// durationScore rates how well actual matches target: 1 within tolerance,
+// falling linearly to 0 once the difference is as large as target itself.
+// Unknown durations score 0.5 so they rank between matches and misses.
+func durationScore(actual, target, tolerance time.Duration) float64 {
+	if actual <= 0 || target <= 0 {
+		return 0.5
+	}
+
+	diff := actual - target
+	if diff < 0 {
+		diff = -diff
+	}
+	if diff <= tolerance {
+		return 1
+	}
+
+	score := 1 - float64(diff-tolerance)/float64(target)
+	if score < 0 {
+		return 0
+	}
+	return score
+}
+
+// formatDuration renders d the way YouTube displays lengths, e.g. "4:05".
+func formatDuration(d time.Duration) string {
+	total := int(d.Round(time.Second).Seconds())
+	hours, minutes, seconds := total/3600, total/60%60, total%60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
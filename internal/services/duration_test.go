@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		hasError bool
+	}{
+		{"245", 245 * time.Second, false},
+		{"4:05", 245 * time.Second, false},
+		{" 04:05 ", 245 * time.Second, false},
+		{"1:02:03", time.Hour + 2*time.Minute + 3*time.Second, false},
+		{"10:00:00", 10 * time.Hour, false},
+		{"", 0, true},
+		{"4:5a", 0, true},
+		{"4:60", 0, true},
+		{"-5", 0, true},
+		{"1:2:3:4", 0, true},
+	}
+
+	for _, test := range tests {
+		result, err := ParseDuration(test.input)
+		if test.hasError {
+			if err == nil {
+				t.Errorf("ParseDuration(%q) expected error, got %v", test.input, result)
+			}
+			continue
+		}
+		if err != nil || result != test.expected {
+			t.Errorf("ParseDuration(%q) = %v, %v; want %v", test.input, result, err, test.expected)
+		}
+	}
+}
+
+func TestDurationScore(t *testing.T) {
+	target := 200 * time.Second
+	tolerance := 10 * time.Second
+
+	if score := durationScore(205*time.Second, target, tolerance); score != 1 {
+		t.Errorf("Expected 1 within tolerance, got %v", score)
+	}
+	if score := durationScore(0, target, tolerance); score != 0.5 {
+		t.Errorf("Expected 0.5 for unknown duration, got %v", score)
+	}
+
+	near := durationScore(230*time.Second, target, tolerance)
+	far := durationScore(600*time.Second, target, tolerance)
+	if near >= 1 || near <= far {
+		t.Errorf("Expected closer durations to score higher, got %v and %v", near, far)
+	}
+	if score := durationScore(10*time.Hour, target, tolerance); score != 0 {
+		t.Errorf("Expected 0 for a 10-hour loop, got %v", score)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	if result := formatDuration(245 * time.Second); result != "4:05" {
+		t.Errorf("Expected 4:05, got %s", result)
+	}
+	if result := formatDuration(10 * time.Hour); result != "10:00:00" {
+		t.Errorf("Expected 10:00:00, got %s", result)
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// ErrRecordingNotFound is returned by a MetadataProvider when the
+// identifier is valid but unknown.
+var ErrRecordingNotFound = errors.New("recording not found")
+
+var (
+	isrcPattern = regexp.MustCompile(`^[A-Z]{2}[A-Z0-9]{3}[0-9]{7}$`)
+	mbidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+)
+
+// Recording is a track as described by a metadata provider.
+type Recording struct {
+	ID       string   `json:"id,omitempty"`
+	Title    string   `json:"title"`
+	Artists  []string `json:"artists"`
+	Duration int      `json:"duration,omitempty"` // seconds, 0 when unknown
+}
+
+// MetadataProvider resolves catalog identifiers to the title and artists
+// needed to search YouTube.
+type MetadataProvider interface {
+	LookupISRC(isrc string) (*Recording, error)
+	LookupRecording(mbid string) (*Recording, error)
+}
+
+// TrackProvider resolves a streaming service's track IDs to recordings.
+type TrackProvider interface {
+	LookupTrack(id string) (*Recording, error)
+}
+
+// NormalizeISRC upper-cases isrc and removes the hyphens and spaces it is
+// often printed with, e.g. "US-RC1-76-07839". It reports whether the result
+// is a well-formed ISRC.
+func NormalizeISRC(isrc string) (string, bool) {
+	isrc = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(isrc)))
+	return isrc, isrcPattern.MatchString(isrc)
+}
+
+// NormalizeMBID lower-cases mbid and reports whether it is a well-formed
+// MusicBrainz identifier.
+func NormalizeMBID(mbid string) (string, bool) {
+	mbid = strings.ToLower(strings.TrimSpace(mbid))
+	return mbid, mbidPattern.MatchString(mbid)
+}
+
+// cachedRecording returns the recording stored in cache under cacheKey,
+// calling fetch and storing its result on a miss.
+func cachedRecording(cache *LRUCache, cacheKey string, fetch func() (*Recording, error)) (*Recording, error) {
+	if cached, found := cache.Get(cacheKey); found {
+		var recording Recording
+		if err := json.Unmarshal([]byte(cached), &recording); err == nil {
+			return &recording, nil
+		}
+	}
+
+	recording, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	if encoded, err := json.Marshal(recording); err == nil {
+		cache.Put(cacheKey, string(encoded))
+	}
+
+	return recording, nil
+}
@@ -0,0 +1,309 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Search sources: the youtube.com results page, or the YouTube Music search
+// backend, which lists songs and videos separately.
+const (
+	SourceYouTube = "youtube"
+	SourceMusic   = "music"
+)
+
+// Kinds of YouTube Music results. Art tracks are the static-image audio
+// uploads of songs, such as the ones on "- Topic" channels.
+const (
+	KindOfficialVideo = "official_video"
+	KindUserVideo     = "user_video"
+	KindArtTrack      = "art_track"
+)
+
+const (
+	defaultMusicBaseURL = "https://music.youtube.com"
+
+	// The YouTube Music web client, as it identifies itself to the
+	// youtubei API.
+	musicClientName    = "WEB_REMIX"
+	musicClientID      = "67"
+	musicClientVersion = "1.20241106.01.00"
+)
+
+var musicVideoKinds = map[string]string{
+	"MUSIC_VIDEO_TYPE_OMV": KindOfficialVideo,
+	"MUSIC_VIDEO_TYPE_UGC": KindUserVideo,
+	"MUSIC_VIDEO_TYPE_ATV": KindArtTrack,
+}
+
+type musicSearchRequest struct {
+	Context struct {
+		Client struct {
+			ClientName    string `json:"clientName"`
+			ClientVersion string `json:"clientVersion"`
+			HL            string `json:"hl,omitempty"`
+			GL            string `json:"gl,omitempty"`
+		} `json:"client"`
+	} `json:"context"`
+	Query string `json:"query"`
+}
+
+// musicSearchResults are the videos and songs a YouTube Music search lists.
+type musicSearchResults struct {
+	Videos []SearchResult `json:"videos"`
+	Songs  []SearchResult `json:"songs"`
+}
+
+// search returns the videos found for the song and, when opts.Source is
+// SourceMusic, the songs. opts must already have defaults applied.
+func (ys *YouTubeService) search(title string, artists []string, opts SearchOptions) ([]SearchResult, []SearchResult, error) {
+	if opts.Source != SourceMusic {
+		results, err := ys.searchResults(title, artists, opts)
+		return results, nil, err
+	}
+
+	results, err := ys.musicSearchResults(title, artists, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return results.Videos, results.Songs, nil
+}
+
+// musicSearchResults returns the cached or freshly fetched YouTube Music
+// search results for the song.
+func (ys *YouTubeService) musicSearchResults(title string, artists []string, opts SearchOptions) (*musicSearchResults, error) {
+	query := ys.buildSearchQuery(title, artists)
+	if opts.Album != "" {
+		query += " " + opts.Album
+	}
+	cacheKey := "music|" + ys.buildCacheKey(title, artists, opts)
+
+	if cached, found := ys.cache.Get(cacheKey); found {
+		var results musicSearchResults
+		if err := json.Unmarshal([]byte(cached), &results); err == nil {
+			log.Printf("Cache HIT for key: %s", cacheKey)
+			return &results, nil
+		}
+	}
+	log.Printf("Cache MISS for key: %s", cacheKey)
+
+	var body musicSearchRequest
+	body.Context.Client.ClientName = musicClientName
+	body.Context.Client.ClientVersion = musicClientVersion
+	body.Context.Client.HL = opts.Language
+	body.Context.Client.GL = opts.Region
+	body.Query = query
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", ys.config.MusicBaseURL+"/youtubei/v1/search?prettyPrint=false", bytes.NewReader(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Origin", ys.config.MusicBaseURL)
+	req.Header.Set("X-YouTube-Client-Name", musicClientID)
+	req.Header.Set("X-YouTube-Client-Version", musicClientVersion)
+
+	resp, err := ys.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch YouTube Music search results: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch YouTube Music search results: status %d", resp.StatusCode)
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var data any
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to decode YouTube Music search results: %w", err)
+	}
+	results := extractMusicSearchResults(data)
+
+	if encoded, err := json.Marshal(results); err == nil {
+		log.Printf("Caching %d videos and %d songs for key: %s", len(results.Videos), len(results.Songs), cacheKey)
+		ys.cache.Put(cacheKey, string(encoded))
+	}
+
+	return results, nil
+}
+
+// extractMusicSearchResults sorts the items of a YouTube Music search
+// response into videos and songs. Items are classified by their music video
+// type, or by the shelf they are on when it is missing. The top result is
+// shown on a card above the shelves and often isn't repeated on them, so the
+// card and the items listed under it come first. Albums, artists and
+// playlists are left out.
+func extractMusicSearchResults(data any) *musicSearchResults {
+	results := &musicSearchResults{}
+	seen := make(map[string]bool)
+
+	add := func(result SearchResult, musicVideoType, shelfTitle string) {
+		if result.ID == "" || seen[result.ID] {
+			return
+		}
+
+		result.Kind = musicVideoKinds[musicVideoType]
+		switch {
+		case result.Kind == KindArtTrack || musicVideoType == "" && shelfTitle == "Songs":
+			// Every song is an audio track, whether or not its type says so.
+			result.Kind = KindArtTrack
+			if len(results.Songs) < maxSearchResults {
+				results.Songs = append(results.Songs, result)
+			}
+		case result.Kind == KindOfficialVideo || result.Kind == KindUserVideo || shelfTitle == "Videos":
+			if len(results.Videos) < maxSearchResults {
+				results.Videos = append(results.Videos, result)
+			}
+		default:
+			return
+		}
+		seen[result.ID] = true
+	}
+
+	addItems := func(shelf map[string]any, shelfTitle string) {
+		contents, _ := shelf["contents"].([]any)
+		for _, content := range contents {
+			if item := objectAt(content, "musicResponsiveListItemRenderer"); item != nil {
+				result, musicVideoType := musicSearchResult(item)
+				add(result, musicVideoType, shelfTitle)
+			}
+		}
+	}
+
+	walkJSON(data, func(key string, value any) bool {
+		shelf, ok := value.(map[string]any)
+		if !ok {
+			return true
+		}
+
+		switch key {
+		case "musicCardShelfRenderer":
+			result, musicVideoType, kind := musicCardResult(shelf)
+			add(result, musicVideoType, kind+"s")
+			// The items under the card are of mixed kinds.
+			addItems(shelf, "")
+			return false
+		case "musicShelfRenderer":
+			addItems(shelf, textAt(shelf, "title"))
+			return false
+		}
+		return true
+	})
+
+	return results
+}
+
+// musicCardResult reads the top result of a YouTube Music search, and its
+// music video type. Its subtitle starts with what it is, such as "Song" or
+// "Video", which is also returned.
+func musicCardResult(card map[string]any) (SearchResult, string, string) {
+	watch := objectAt(card, "thumbnailOverlay", "musicItemThumbnailOverlayRenderer", "content",
+		"musicPlayButtonRenderer", "playNavigationEndpoint", "watchEndpoint")
+	if stringAt(watch, "videoId") == "" {
+		runs, _ := objectAt(card, "title")["runs"].([]any)
+		if len(runs) > 0 {
+			watch = objectAt(runs[0], "navigationEndpoint", "watchEndpoint")
+		}
+	}
+
+	result := SearchResult{ID: stringAt(watch, "videoId"), Title: textAt(card, "title")}
+	musicVideoType := stringAt(objectAt(watch, "watchEndpointMusicSupportedConfigs", "watchEndpointMusicConfig"), "musicVideoType")
+
+	artists, kind, duration := musicSubtitle(objectAt(card, "subtitle"))
+	result.Channel = strings.Join(artists, ", ")
+	result.Duration = duration
+
+	return result, musicVideoType, kind
+}
+
+// musicSearchResult reads the song or video an item of a YouTube Music
+// shelf plays, and its music video type such as MUSIC_VIDEO_TYPE_ATV.
+func musicSearchResult(item map[string]any) (SearchResult, string) {
+	watch := objectAt(item, "overlay", "musicItemThumbnailOverlayRenderer", "content",
+		"musicPlayButtonRenderer", "playNavigationEndpoint", "watchEndpoint")
+
+	result := SearchResult{ID: stringAt(objectAt(item, "playlistItemData"), "videoId")}
+	if result.ID == "" {
+		result.ID = stringAt(watch, "videoId")
+	}
+	musicVideoType := stringAt(objectAt(watch, "watchEndpointMusicSupportedConfigs", "watchEndpointMusicConfig"), "musicVideoType")
+
+	columns, _ := item["flexColumns"].([]any)
+	for i, column := range columns {
+		renderer := objectAt(column, "musicResponsiveListItemFlexColumnRenderer")
+		if i == 0 {
+			result.Title = textAt(renderer, "text")
+			continue
+		}
+
+		artists, first, duration := musicSubtitle(objectAt(renderer, "text"))
+		if duration > 0 {
+			result.Duration = duration
+		}
+		if result.Channel == "" {
+			if len(artists) > 0 {
+				result.Channel = strings.Join(artists, ", ")
+			} else if i == 1 {
+				// Without links, the artist comes first.
+				result.Channel = first
+			}
+		}
+	}
+
+	return result, musicVideoType
+}
+
+// musicSubtitle reads subtitle runs like "Loreen • Euphoria • 3:01", which
+// link artists and channels to their pages. It returns the linked artists,
+// the text of the first run and the duration in seconds, if any.
+func musicSubtitle(text map[string]any) ([]string, string, int) {
+	var artists []string
+	first, seconds := "", 0
+
+	runs, _ := text["runs"].([]any)
+	for _, run := range runs {
+		r, ok := run.(map[string]any)
+		if !ok {
+			continue
+		}
+		text := strings.TrimSpace(stringAt(r, "text"))
+		if first == "" {
+			first = text
+		}
+
+		switch stringAt(objectAt(r, "navigationEndpoint", "browseEndpoint", "browseEndpointContextSupportedConfigs", "browseEndpointContextMusicConfig"), "pageType") {
+		case "MUSIC_PAGE_TYPE_ARTIST", "MUSIC_PAGE_TYPE_USER_CHANNEL":
+			artists = append(artists, text)
+		}
+		if duration, err := ParseDuration(text); err == nil && strings.Contains(text, ":") {
+			seconds = int(duration.Seconds())
+		}
+	}
+
+	return artists, first, seconds
+}
+
+// objectAt follows keys through nested JSON objects, returning nil when
+// one is missing.
+func objectAt(value any, keys ...string) map[string]any {
+	object, _ := value.(map[string]any)
+	for _, key := range keys {
+		object, _ = object[key].(map[string]any)
+	}
+	return object
+}
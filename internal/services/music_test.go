@@ -0,0 +1,248 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func musicRun(text, pageType string) map[string]any {
+	run := map[string]any{"text": text}
+	if pageType != "" {
+		run["navigationEndpoint"] = map[string]any{"browseEndpoint": map[string]any{
+			"browseEndpointContextSupportedConfigs": map[string]any{
+				"browseEndpointContextMusicConfig": map[string]any{"pageType": pageType},
+			},
+		}}
+	}
+	return run
+}
+
+// musicItem is a musicResponsiveListItemRenderer playing id, or an album
+// when id is empty.
+func musicItem(id, title, musicVideoType string, subtitle ...map[string]any) map[string]any {
+	runs := make([]any, len(subtitle))
+	for i, run := range subtitle {
+		runs[i] = run
+	}
+	renderer := map[string]any{
+		"flexColumns": []any{
+			map[string]any{"musicResponsiveListItemFlexColumnRenderer": map[string]any{
+				"text": map[string]any{"runs": []any{map[string]any{"text": title}}},
+			}},
+			map[string]any{"musicResponsiveListItemFlexColumnRenderer": map[string]any{
+				"text": map[string]any{"runs": runs},
+			}},
+		},
+	}
+	if id != "" {
+		renderer["playlistItemData"] = map[string]any{"videoId": id}
+		renderer["overlay"] = map[string]any{"musicItemThumbnailOverlayRenderer": map[string]any{
+			"content": map[string]any{"musicPlayButtonRenderer": map[string]any{
+				"playNavigationEndpoint": map[string]any{"watchEndpoint": map[string]any{
+					"videoId": id,
+					"watchEndpointMusicSupportedConfigs": map[string]any{
+						"watchEndpointMusicConfig": map[string]any{"musicVideoType": musicVideoType},
+					},
+				}},
+			}},
+		}}
+	}
+	return map[string]any{"musicResponsiveListItemRenderer": renderer}
+}
+
+func musicShelf(title string, items ...map[string]any) map[string]any {
+	contents := make([]any, len(items))
+	for i, item := range items {
+		contents[i] = item
+	}
+	return map[string]any{"musicShelfRenderer": map[string]any{
+		"title":    map[string]any{"runs": []any{map[string]any{"text": title}}},
+		"contents": contents,
+	}}
+}
+
+// musicCard is a musicCardShelfRenderer, YouTube Music's top result,
+// playing id, with items listed under it.
+func musicCard(id, title, musicVideoType string, subtitle []map[string]any, items ...map[string]any) map[string]any {
+	runs := make([]any, len(subtitle))
+	for i, run := range subtitle {
+		runs[i] = run
+	}
+	contents := make([]any, len(items))
+	for i, item := range items {
+		contents[i] = item
+	}
+	return map[string]any{"musicCardShelfRenderer": map[string]any{
+		"header": map[string]any{"musicCardShelfHeaderBasicRenderer": map[string]any{
+			"title": map[string]any{"runs": []any{map[string]any{"text": "Top result"}}},
+		}},
+		"title": map[string]any{"runs": []any{map[string]any{
+			"text": title,
+			"navigationEndpoint": map[string]any{"watchEndpoint": map[string]any{
+				"videoId": id,
+				"watchEndpointMusicSupportedConfigs": map[string]any{
+					"watchEndpointMusicConfig": map[string]any{"musicVideoType": musicVideoType},
+				},
+			}},
+		}}},
+		"subtitle": map[string]any{"runs": runs},
+		"contents": contents,
+	}}
+}
+
+// musicSearchResponse is a YouTube Music search for Euphoria by Loreen.
+func musicSearchResponse() map[string]any {
+	separator := musicRun(" • ", "")
+	artist := musicRun("Loreen", "MUSIC_PAGE_TYPE_ARTIST")
+
+	return map[string]any{"contents": map[string]any{"tabbedSearchResultsRenderer": map[string]any{
+		"tabs": []any{map[string]any{"tabRenderer": map[string]any{"content": map[string]any{
+			"sectionListRenderer": map[string]any{"contents": []any{
+				musicShelf("Songs",
+					musicItem("euphoriaATV", "Euphoria", "MUSIC_VIDEO_TYPE_ATV",
+						artist, separator, musicRun("Heal", "MUSIC_PAGE_TYPE_ALBUM"), separator, musicRun("3:01", "")),
+				),
+				musicShelf("Videos",
+					musicItem("euphoriaUGC", "Euphoria", "MUSIC_VIDEO_TYPE_UGC",
+						musicRun("Loreen", "MUSIC_PAGE_TYPE_USER_CHANNEL"), separator, musicRun("2M views", ""), separator, musicRun("3:04", "")),
+					musicItem("euphoriaOMV", "Euphoria", "MUSIC_VIDEO_TYPE_OMV",
+						artist, separator, musicRun("100M views", ""), separator, musicRun("3:04", "")),
+				),
+				musicShelf("Albums",
+					musicItem("", "Heal", "", musicRun("Album", ""), separator, artist),
+				),
+			}},
+		}}}},
+	}}}
+}
+
+func TestExtractMusicSearchResults(t *testing.T) {
+	results := extractMusicSearchResults(musicSearchResponse())
+
+	if len(results.Songs) != 1 {
+		t.Fatalf("Expected 1 song, got %+v", results.Songs)
+	}
+	song := results.Songs[0]
+	if song.ID != "euphoriaATV" || song.Title != "Euphoria" || song.Channel != "Loreen" || song.Duration != 181 || song.Kind != KindArtTrack {
+		t.Errorf("Unexpected song %+v", song)
+	}
+
+	if len(results.Videos) != 2 {
+		t.Fatalf("Expected 2 videos, got %+v", results.Videos)
+	}
+	if results.Videos[0].Kind != KindUserVideo || results.Videos[1].Kind != KindOfficialVideo {
+		t.Errorf("Expected a user video and an official video, got %+v", results.Videos)
+	}
+	if results.Videos[1].Channel != "Loreen" || results.Videos[1].Duration != 184 {
+		t.Errorf("Unexpected video %+v", results.Videos[1])
+	}
+}
+
+func TestExtractMusicSearchResults_ShelfFallback(t *testing.T) {
+	data := map[string]any{"contents": []any{
+		musicShelf("Songs", musicItem("song0000000", "Euphoria", "", musicRun("Loreen", ""))),
+		musicShelf("Videos", musicItem("video000000", "Euphoria", "", musicRun("Loreen", ""))),
+	}}
+
+	results := extractMusicSearchResults(data)
+
+	if len(results.Songs) != 1 || results.Songs[0].ID != "song0000000" || results.Songs[0].Channel != "Loreen" {
+		t.Errorf("Expected the Songs shelf item as a song, got %+v", results.Songs)
+	}
+	if len(results.Videos) != 1 || results.Videos[0].ID != "video000000" {
+		t.Errorf("Expected the Videos shelf item as a video, got %+v", results.Videos)
+	}
+}
+
+func TestExtractMusicSearchResults_TopResultCard(t *testing.T) {
+	separator := musicRun(" • ", "")
+	artist := musicRun("Loreen", "MUSIC_PAGE_TYPE_ARTIST")
+
+	// The top result is left off the Songs shelf, as YouTube Music does.
+	data := map[string]any{"contents": []any{
+		musicCard("tattooATV00", "Tattoo", "MUSIC_VIDEO_TYPE_ATV",
+			[]map[string]any{musicRun("Song", ""), separator, artist, separator, musicRun("Tattoo", "MUSIC_PAGE_TYPE_ALBUM"), separator, musicRun("3:03", "")},
+			musicItem("tattooOMV00", "Tattoo", "MUSIC_VIDEO_TYPE_OMV", artist, separator, musicRun("3:15", "")),
+		),
+		musicShelf("Songs",
+			musicItem("tattooLive0", "Tattoo (Live)", "MUSIC_VIDEO_TYPE_ATV", artist, separator, musicRun("3:20", "")),
+		),
+	}}
+
+	results := extractMusicSearchResults(data)
+
+	if len(results.Songs) != 2 {
+		t.Fatalf("Expected the top result and the shelf's song, got %+v", results.Songs)
+	}
+	top := results.Songs[0]
+	if top.ID != "tattooATV00" || top.Title != "Tattoo" || top.Channel != "Loreen" || top.Duration != 183 || top.Kind != KindArtTrack {
+		t.Errorf("Unexpected top result %+v", top)
+	}
+	if results.Songs[1].ID != "tattooLive0" {
+		t.Errorf("Expected the shelf's song after the top result, got %+v", results.Songs[1])
+	}
+	if len(results.Videos) != 1 || results.Videos[0].ID != "tattooOMV00" || results.Videos[0].Kind != KindOfficialVideo {
+		t.Errorf("Expected the video listed under the card, got %+v", results.Videos)
+	}
+}
+
+func TestYouTubeService_ResolveMusic(t *testing.T) {
+	requests := 0
+	ys := newStubService(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != "POST" || r.URL.Path != "/youtubei/v1/search" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var body musicSearchRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode request: %v", err)
+		}
+		if body.Context.Client.ClientName != "WEB_REMIX" || body.Query != "Euphoria Loreen" || body.Context.Client.GL != "SE" {
+			t.Errorf("Unexpected request body %+v", body)
+		}
+
+		json.NewEncoder(w).Encode(musicSearchResponse())
+	})
+
+	for range 2 {
+		resolution, err := ys.Resolve("Euphoria", []string{"Loreen"}, SearchOptions{Source: SourceMusic, Region: "SE"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// The official video wins its tie with the fan upload.
+		if resolution.Video == nil || resolution.Video.ID != "euphoriaOMV" {
+			t.Errorf("Expected the official video, got %+v", resolution.Video)
+		}
+		if resolution.Audio == nil || resolution.Audio.ID != "euphoriaATV" || resolution.Audio.Kind != KindArtTrack {
+			t.Errorf("Expected the art track, got %+v", resolution.Audio)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected the search to be cached, got %d requests", requests)
+	}
+}
+
+func TestYouTubeService_ResolveMusicReportsSkippedSongs(t *testing.T) {
+	ys := newStubService(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(musicSearchResponse())
+	})
+
+	resolution, err := ys.Resolve("Euphoria", []string{"Loreen"}, SearchOptions{Source: SourceMusic, ExcludeIDs: []string{"euphoriaATV"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resolution.Audio != nil {
+		t.Errorf("Expected no audio track, got %+v", resolution.Audio)
+	}
+	if len(resolution.Skipped) != 1 || resolution.Skipped[0].ID != "euphoriaATV" {
+		t.Errorf("Expected the excluded song to be reported as skipped, got %+v", resolution.Skipped)
+	}
+	if resolution.Video == nil || resolution.Video.ID != "euphoriaOMV" {
+		t.Errorf("Expected the official video, got %+v", resolution.Video)
+	}
+}
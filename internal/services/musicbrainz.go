@@ -0,0 +1,118 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMusicBrainzBaseURL = "https://musicbrainz.org"
+
+	// MusicBrainz asks clients to identify themselves with a meaningful
+	// user agent and contact URL.
+	musicBrainzUserAgent = "youtube-music-video-api/1.0 ( https://github.com/artmann/youtube-music-video-api )"
+)
+
+// MusicBrainzClient is a MetadataProvider backed by the MusicBrainz web
+// service, or any server implementing the same /ws/2 API.
+type MusicBrainzClient struct {
+	baseURL string
+	client  *http.Client
+	cache   *LRUCache
+}
+
+func NewMusicBrainzClient(baseURL string, timeout time.Duration) *MusicBrainzClient {
+	if baseURL == "" {
+		baseURL = defaultMusicBrainzBaseURL
+	}
+	return &MusicBrainzClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+		cache:   NewLRUCache(5000),
+	}
+}
+
+type musicBrainzRecording struct {
+	ID           string `json:"id"`
+	Title        string `json:"title"`
+	Length       int    `json:"length"` // milliseconds
+	ArtistCredit []struct {
+		Name string `json:"name"`
+	} `json:"artist-credit"`
+}
+
+func (r *musicBrainzRecording) recording() *Recording {
+	recording := &Recording{
+		ID:       r.ID,
+		Title:    r.Title,
+		Duration: (r.Length + 500) / 1000,
+	}
+	for _, credit := range r.ArtistCredit {
+		recording.Artists = append(recording.Artists, credit.Name)
+	}
+	return recording
+}
+
+// LookupISRC returns the first recording MusicBrainz lists for isrc, which
+// must already be normalized. Results are cached by ISRC.
+func (mb *MusicBrainzClient) LookupISRC(isrc string) (*Recording, error) {
+	return cachedRecording(mb.cache, "isrc:"+isrc, func() (*Recording, error) {
+		var response struct {
+			Recordings []musicBrainzRecording `json:"recordings"`
+		}
+		if err := mb.get("/ws/2/isrc/"+url.PathEscape(isrc), &response); err != nil {
+			return nil, err
+		}
+		if len(response.Recordings) == 0 {
+			return nil, ErrRecordingNotFound
+		}
+		return response.Recordings[0].recording(), nil
+	})
+}
+
+// LookupRecording returns the recording with the given MBID, which must
+// already be normalized. Results are cached by MBID.
+func (mb *MusicBrainzClient) LookupRecording(mbid string) (*Recording, error) {
+	return cachedRecording(mb.cache, "recording:"+mbid, func() (*Recording, error) {
+		var response musicBrainzRecording
+		if err := mb.get("/ws/2/recording/"+url.PathEscape(mbid), &response); err != nil {
+			return nil, err
+		}
+		return response.recording(), nil
+	})
+}
+
+// get fetches path with artist credits included and decodes the JSON
+// response into v.
+func (mb *MusicBrainzClient) get(path string, v any) error {
+	requestURL := mb.baseURL + path + "?" + url.Values{"inc": {"artist-credits"}, "fmt": {"json"}}.Encode()
+
+	req, err := http.NewRequest("GET", requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", musicBrainzUserAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := mb.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to query MusicBrainz: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrRecordingNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("failed to query MusicBrainz: status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode MusicBrainz response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNormalizeISRC(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"USRC17607839", "USRC17607839", true},
+		{"us-rc1-76-07839", "USRC17607839", true},
+		{" GB AYE 69 00531 ", "GBAYE6900531", true},
+		{"USRC1760783", "USRC1760783", false},
+		{"12RC17607839", "12RC17607839", false},
+		{"USRC1760783X", "USRC1760783X", false},
+	}
+
+	for _, tt := range tests {
+		isrc, valid := NormalizeISRC(tt.input)
+		if isrc != tt.expected || valid != tt.valid {
+			t.Errorf("NormalizeISRC(%q) = %q, %v; expected %q, %v", tt.input, isrc, valid, tt.expected, tt.valid)
+		}
+	}
+}
+
+func TestMusicBrainzClient_LookupISRC(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/ws/2/isrc/USRC17607839" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("inc") != "artist-credits" || r.URL.Query().Get("fmt") != "json" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("User-Agent") != musicBrainzUserAgent {
+			t.Errorf("Unexpected user agent %q", r.Header.Get("User-Agent"))
+		}
+		w.Write([]byte(`{"isrc":"USRC17607839","recordings":[{"id":"b1a9c0e9-d987-4042-ae91-78d6a3267d69","title":"Never Gonna Give You Up","length":213573,` +
+			`"artist-credit":[{"name":"Rick Astley","joinphrase":""}]}]}`))
+	}))
+	defer server.Close()
+
+	mb := NewMusicBrainzClient(server.URL, 0)
+
+	for range 2 {
+		recording, err := mb.LookupISRC("USRC17607839")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if recording.Title != "Never Gonna Give You Up" || len(recording.Artists) != 1 || recording.Artists[0] != "Rick Astley" {
+			t.Errorf("Unexpected recording %+v", recording)
+		}
+		if recording.Duration != 214 {
+			t.Errorf("Expected duration 214, got %d", recording.Duration)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected the second lookup to be cached, got %d requests", requests)
+	}
+}
+
+func TestMusicBrainzClient_LookupISRCNotFound(t *testing.T) {
+	for _, handler := range []http.HandlerFunc{
+		func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) },
+		func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`{"recordings":[]}`)) },
+	} {
+		server := httptest.NewServer(handler)
+
+		_, err := NewMusicBrainzClient(server.URL, 0).LookupISRC("USRC17607839")
+		if !errors.Is(err, ErrRecordingNotFound) {
+			t.Errorf("Expected ErrRecordingNotFound, got %v", err)
+		}
+
+		server.Close()
+	}
+}
+
+func TestNormalizeMBID(t *testing.T) {
+	if mbid, ok := NormalizeMBID(" B1A9C0E9-D987-4042-AE91-78D6A3267D69 "); !ok || mbid != "b1a9c0e9-d987-4042-ae91-78d6a3267d69" {
+		t.Errorf("Expected normalized MBID, got %q, %v", mbid, ok)
+	}
+	for _, invalid := range []string{"", "b1a9c0e9d9874042ae9178d6a3267d69", "g1a9c0e9-d987-4042-ae91-78d6a3267d69"} {
+		if _, ok := NormalizeMBID(invalid); ok {
+			t.Errorf("Expected %q to be rejected", invalid)
+		}
+	}
+}
+
+func TestMusicBrainzClient_LookupRecording(t *testing.T) {
+	const mbid = "b1a9c0e9-d987-4042-ae91-78d6a3267d69"
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/ws/2/recording/"+mbid {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"id":"` + mbid + `","title":"Under Pressure","length":248000,` +
+			`"artist-credit":[{"name":"Queen","joinphrase":" & "},{"name":"David Bowie","joinphrase":""}]}`))
+	}))
+	defer server.Close()
+
+	mb := NewMusicBrainzClient(server.URL+"/", 0)
+
+	for range 2 {
+		recording, err := mb.LookupRecording(mbid)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if recording.ID != mbid || recording.Title != "Under Pressure" || recording.Duration != 248 {
+			t.Errorf("Unexpected recording %+v", recording)
+		}
+		if len(recording.Artists) != 2 || recording.Artists[1] != "David Bowie" {
+			t.Errorf("Expected both credited artists, got %v", recording.Artists)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected the second lookup to be cached, got %d requests", requests)
+	}
+}
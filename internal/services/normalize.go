@@ -0,0 +1,93 @@
+package services
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const titleDecoration = `(?:\d{4}\s+)?(?:digital(?:ly)?\s+)?remaster(?:ed)?(?:\s+\d{4})?(?:\s+version)?|` +
+	`radio (?:edit|version)|single (?:edit|version)|album version|(?:explicit|clean)(?: version)?|` +
+	`official (?:music |lyric )?(?:video|audio)|lyric video|lyrics|audio|mono|stereo`
+
+var (
+	bracketedFeaturePattern = regexp.MustCompile(`(?i)\s*[\(\[]\s*(?:feat\.?|ft\.?|featuring|with)\s[^\)\]]*[\)\]]`)
+	trailingFeaturePattern  = regexp.MustCompile(`(?i)\s+(?:feat\.?|ft\.?|featuring)\s.*$`)
+	bracketedDecoration     = regexp.MustCompile(`(?i)\s*[\(\[]\s*(?:` + titleDecoration + `)\s*[\)\]]`)
+	dashedDecoration        = regexp.MustCompile(`(?i)\s+[-–—]\s+(?:` + titleDecoration + `)\s*$`)
+)
+
+// letterFolds covers letters that don't decompose into a base letter and a
+// combining mark, so stripping marks alone wouldn't fold them.
+var letterFolds = strings.NewReplacer(
+	"ø", "o", "æ", "ae", "œ", "oe", "ł", "l", "đ", "d", "ð", "d", "þ", "th", "ı", "i",
+)
+
+// NormalizeText case-folds s, applies NFKC, strips diacritics and
+// punctuation and collapses whitespace, so that trivially different
+// spellings compare equal.
+func NormalizeText(s string) string {
+	s = norm.NFKC.String(s)
+	s = cases.Fold().String(s)
+
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), s)
+	if err == nil {
+		s = folded
+	}
+	s = letterFolds.Replace(s)
+	s = strings.ReplaceAll(s, "&", " and ")
+
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return r
+		}
+		return ' '
+	}, s)
+
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// StripTitleDecorations removes featured-artist credits and release
+// decorations such as "(Remastered 2011)" or "- Radio Edit" from a title.
+func StripTitleDecorations(title string) string {
+	for {
+		stripped := bracketedFeaturePattern.ReplaceAllString(title, "")
+		stripped = trailingFeaturePattern.ReplaceAllString(stripped, "")
+		stripped = bracketedDecoration.ReplaceAllString(stripped, "")
+		stripped = dashedDecoration.ReplaceAllString(stripped, "")
+		stripped = strings.TrimSpace(stripped)
+
+		if stripped == title {
+			return title
+		}
+		title = stripped
+	}
+}
+
+func NormalizeTitle(title string) string {
+	normalized := NormalizeText(StripTitleDecorations(title))
+	if normalized == "" {
+		// Titles made only of punctuation or decorations still need a key.
+		return strings.ToLower(strings.TrimSpace(title))
+	}
+	return normalized
+}
+
+// NormalizeArtists normalizes each artist, drops duplicates and sorts them
+// so that credit order doesn't matter.
+func NormalizeArtists(artists []string) []string {
+	normalized := make([]string, 0, len(artists))
+	for _, artist := range artists {
+		if n := NormalizeText(artist); n != "" {
+			normalized = append(normalized, n)
+		}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
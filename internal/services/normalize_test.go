@@ -0,0 +1,72 @@
+package services
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Euphoria", "euphoria"},
+		{"  EUPHORIA  ", "euphoria"},
+		{"Beyoncé", "beyonce"},
+		{"Sigur Rós", "sigur ros"},
+		{"Mötley Crüe", "motley crue"},
+		{"Røyksopp", "royksopp"},
+		{"Straße", "strasse"},
+		{"ＡＢＣ", "abc"},
+		{"Don't Stop Me Now!", "don t stop me now"},
+		{"Simon & Garfunkel", "simon and garfunkel"},
+		{"AC/DC", "ac dc"},
+		{"Хорошо", "хорошо"},
+	}
+
+	for _, test := range tests {
+		if result := NormalizeText(test.input); result != test.expected {
+			t.Errorf("NormalizeText(%q) = %q; want %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestStripTitleDecorations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Song (feat. Y)", "Song"},
+		{"Song [ft. Y & Z]", "Song"},
+		{"Song feat. Y", "Song"},
+		{"Stay (with Justin Bieber)", "Stay"},
+		{"Here Comes the Sun (Remastered 2011)", "Here Comes the Sun"},
+		{"Here Comes the Sun - Remastered 2011", "Here Comes the Sun"},
+		{"Here Comes the Sun - 2009 Remaster", "Here Comes the Sun"},
+		{"Blinding Lights - Radio Edit", "Blinding Lights"},
+		{"Song (Official Music Video) [Remastered]", "Song"},
+		{"Song (feat. Y) - Radio Edit", "Song"},
+		{"Live and Let Die", "Live and Let Die"},
+		{"Song (Live)", "Song (Live)"},
+	}
+
+	for _, test := range tests {
+		if result := StripTitleDecorations(test.input); result != test.expected {
+			t.Errorf("StripTitleDecorations(%q) = %q; want %q", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestNormalizeTitle_FallsBackForPunctuation(t *testing.T) {
+	if result := NormalizeTitle("!!!"); result != "!!!" {
+		t.Errorf("Expected punctuation-only title to be kept, got %q", result)
+	}
+}
+
+func TestNormalizeArtists(t *testing.T) {
+	result := NormalizeArtists([]string{"X", "Loreen", "loreen", " ", "Beyoncé"})
+	expected := []string{"beyonce", "loreen", "x"}
+	if !slices.Equal(result, expected) {
+		t.Errorf("NormalizeArtists = %v; want %v", result, expected)
+	}
+}
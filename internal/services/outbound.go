@@ -0,0 +1,58 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// do sends req with the configured headers, rotating user agents and
+// routing through the proxy pool when one is configured.
+func (ys *YouTubeService) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", ys.nextUserAgent())
+	if ys.config.AcceptLanguage != "" {
+		req.Header.Set("Accept-Language", ys.config.AcceptLanguage)
+	}
+	for name, value := range ys.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	if ys.proxies == nil {
+		return ys.client.Do(req)
+	}
+
+	proxy, err := ys.proxies.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := proxy.client.Do(req)
+	if err != nil {
+		ys.proxies.ReportFailure(proxy)
+		return nil, fmt.Errorf("proxy %s: %w", proxy.URL.Redacted(), err)
+	}
+
+	if isProxyFailureStatus(resp.StatusCode) {
+		ys.proxies.ReportFailure(proxy)
+	} else {
+		ys.proxies.ReportSuccess(proxy)
+	}
+
+	return resp, nil
+}
+
+func (ys *YouTubeService) nextUserAgent() string {
+	agents := ys.config.UserAgents
+	index := ys.userAgentIndex.Add(1) - 1
+	return agents[index%uint64(len(agents))]
+}
+
+// isProxyFailureStatus reports whether a status code most likely means the
+// proxy itself is blocked or broken rather than the request being bad.
+// YouTube answers a blocked proxy the same way it answers during its own
+// outages, so a proxy retired for these statuses is tried again later.
+func isProxyFailureStatus(status int) bool {
+	return status == http.StatusTooManyRequests ||
+		status == http.StatusForbidden ||
+		status == http.StatusProxyAuthRequired ||
+		status >= http.StatusInternalServerError
+}
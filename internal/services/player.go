@@ -0,0 +1,200 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+const playerResponseMarker = "ytInitialPlayerResponse = "
+
+// PlayerInfo is the subset of a watch page's player response the service
+// cares about.
+type PlayerInfo struct {
+	VideoID            string   `json:"videoId"`
+	Status             string   `json:"status"`
+	Reason             string   `json:"reason,omitempty"`
+	AvailableCountries []string `json:"availableCountries,omitempty"`
+	Embeddable         *bool    `json:"embeddable,omitempty"` // nil when the page doesn't say
+	AgeRestricted      bool     `json:"ageRestricted"`
+}
+
+type Availability struct {
+	Region         string   `json:"region,omitempty"`
+	Playable       bool     `json:"playable"`
+	Status         string   `json:"status"`
+	Reason         string   `json:"reason,omitempty"`
+	AllowedRegions []string `json:"allowedRegions,omitempty"`
+}
+
+type playerResponse struct {
+	PlayabilityStatus struct {
+		Status                     string `json:"status"`
+		Reason                     string `json:"reason"`
+		PlayableInEmbed            *bool  `json:"playableInEmbed"`
+		DesktopLegacyAgeGateReason int    `json:"desktopLegacyAgeGateReason"`
+	} `json:"playabilityStatus"`
+	VideoDetails struct {
+		VideoID       string `json:"videoId"`
+		Title         string `json:"title"`
+		Author        string `json:"author"`
+		ChannelID     string `json:"channelId"`
+		LengthSeconds string `json:"lengthSeconds"`
+		ViewCount     string `json:"viewCount"`
+		Thumbnail     struct {
+			Thumbnails []Thumbnail `json:"thumbnails"`
+		} `json:"thumbnail"`
+	} `json:"videoDetails"`
+	Microformat struct {
+		PlayerMicroformatRenderer struct {
+			AvailableCountries []string `json:"availableCountries"`
+			IsFamilySafe       *bool    `json:"isFamilySafe"`
+			PublishDate        string   `json:"publishDate"`
+		} `json:"playerMicroformatRenderer"`
+	} `json:"microformat"`
+}
+
+// ageRestricted reports whether logged-out viewers are shown an age gate
+// instead of the video.
+func (player *playerResponse) ageRestricted() bool {
+	status := player.PlayabilityStatus
+	if status.DesktopLegacyAgeGateReason != 0 {
+		return true
+	}
+	if status.Status == "LOGIN_REQUIRED" && strings.Contains(strings.ToLower(status.Reason), "age") {
+		return true
+	}
+	familySafe := player.Microformat.PlayerMicroformatRenderer.IsFamilySafe
+	return familySafe != nil && !*familySafe
+}
+
+// GetPlayerInfo fetches the watch page for videoID and extracts its
+// playability status, regional restrictions, whether it can be embedded and
+// whether it is age-restricted.
+func (ys *YouTubeService) GetPlayerInfo(videoID string) (*PlayerInfo, error) {
+	if cached, found := ys.players.Get(videoID); found {
+		var info PlayerInfo
+		if err := json.Unmarshal([]byte(cached), &info); err == nil {
+			return &info, nil
+		}
+	}
+
+	html, err := ys.fetchWatchPage(videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	info, _, err := ys.cacheWatchPage(videoID, html)
+	return info, err
+}
+
+// cacheWatchPage extracts both the player info and the video details from
+// a watch page and caches them, so that whichever is asked for second
+// doesn't fetch the page again. The details are nil when the page has none.
+func (ys *YouTubeService) cacheWatchPage(videoID, html string) (*PlayerInfo, *VideoDetails, error) {
+	player, err := extractPlayerResponse(html)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	status := player.PlayabilityStatus.Status
+	if status == "" {
+		status = "UNKNOWN"
+	}
+
+	info := &PlayerInfo{
+		VideoID:            videoID,
+		Status:             status,
+		Reason:             player.PlayabilityStatus.Reason,
+		AvailableCountries: player.Microformat.PlayerMicroformatRenderer.AvailableCountries,
+		Embeddable:         player.PlayabilityStatus.PlayableInEmbed,
+		AgeRestricted:      player.ageRestricted(),
+	}
+	if encoded, err := json.Marshal(info); err == nil {
+		ys.players.Put(videoID, string(encoded))
+	}
+
+	details := newVideoDetails(player, html)
+	if details != nil {
+		if encoded, err := json.Marshal(details); err == nil {
+			ys.videos.Put(videoID, string(encoded))
+		}
+	}
+
+	return info, details, nil
+}
+
+func (ys *YouTubeService) fetchWatchPage(videoID string) (string, error) {
+	watchURL := ys.config.BaseURL + "/watch?" + url.Values{"v": {videoID}}.Encode()
+
+	req, err := http.NewRequest("GET", watchURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := ys.do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch watch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch watch page: status %d", resp.StatusCode)
+	}
+
+	html, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return string(html), nil
+}
+
+// CheckAvailability reports whether videoID can be played, and when region
+// is set, whether it can be played there.
+func (ys *YouTubeService) CheckAvailability(videoID, region string) (*Availability, error) {
+	info, err := ys.GetPlayerInfo(videoID)
+	if err != nil {
+		return nil, err
+	}
+	return info.AvailabilityIn(region), nil
+}
+
+func (info *PlayerInfo) AvailabilityIn(region string) *Availability {
+	availability := &Availability{
+		Region:         region,
+		Playable:       info.Status == "OK",
+		Status:         info.Status,
+		Reason:         info.Reason,
+		AllowedRegions: info.AvailableCountries,
+	}
+
+	if availability.Playable && region != "" && len(info.AvailableCountries) > 0 &&
+		!slices.Contains(info.AvailableCountries, region) {
+		availability.Playable = false
+		availability.Status = "UNPLAYABLE"
+		availability.Reason = "The video is not available in " + region + "."
+	}
+
+	return availability
+}
+
+func extractPlayerResponse(html string) (*playerResponse, error) {
+	index := strings.Index(html, playerResponseMarker)
+	if index == -1 {
+		return nil, errors.New("no player response found in watch page")
+	}
+
+	var player playerResponse
+	decoder := json.NewDecoder(strings.NewReader(html[index+len(playerResponseMarker):]))
+	if err := decoder.Decode(&player); err != nil {
+		return nil, fmt.Errorf("failed to parse player response: %w", err)
+	}
+
+	return &player, nil
+}
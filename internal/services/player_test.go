@@ -0,0 +1,138 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// watchPage renders a minimal watch page around the given player response.
+func watchPage(player map[string]any) string {
+	encoded, _ := json.Marshal(player)
+	return `<html><script>var ytInitialPlayerResponse = ` + string(encoded) + `;var meta = {};</script></html>`
+}
+
+func playablePlayer(status string, countries ...string) map[string]any {
+	return map[string]any{
+		"playabilityStatus": map[string]any{"status": status},
+		"microformat": map[string]any{
+			"playerMicroformatRenderer": map[string]any{"availableCountries": countries},
+		},
+	}
+}
+
+func TestExtractPlayerResponse(t *testing.T) {
+	player, err := extractPlayerResponse(watchPage(playablePlayer("OK", "SE", "NO")))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if player.PlayabilityStatus.Status != "OK" {
+		t.Errorf("Expected status OK, got %q", player.PlayabilityStatus.Status)
+	}
+	countries := player.Microformat.PlayerMicroformatRenderer.AvailableCountries
+	if len(countries) != 2 || countries[0] != "SE" {
+		t.Errorf("Expected [SE NO], got %v", countries)
+	}
+
+	if _, err := extractPlayerResponse("<html></html>"); err == nil {
+		t.Error("Expected error when no player response is present")
+	}
+	if _, err := extractPlayerResponse("var ytInitialPlayerResponse = {broken"); err == nil {
+		t.Error("Expected error for malformed player response")
+	}
+}
+
+func TestPlayerInfo_AvailabilityIn(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     PlayerInfo
+		region   string
+		playable bool
+	}{
+		{"Playable everywhere", PlayerInfo{Status: "OK"}, "US", true},
+		{"Allowed region", PlayerInfo{Status: "OK", AvailableCountries: []string{"SE", "US"}}, "US", true},
+		{"Blocked region", PlayerInfo{Status: "OK", AvailableCountries: []string{"SE"}}, "US", false},
+		{"No region requested", PlayerInfo{Status: "OK", AvailableCountries: []string{"SE"}}, "", true},
+		{"Unplayable", PlayerInfo{Status: "UNPLAYABLE", Reason: "Video unavailable"}, "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			availability := test.info.AvailabilityIn(test.region)
+			if availability.Playable != test.playable {
+				t.Errorf("Expected playable=%v, got %+v", test.playable, availability)
+			}
+			if !availability.Playable && availability.Reason == "" {
+				t.Error("Expected a reason for unplayable video")
+			}
+		})
+	}
+}
+
+func TestYouTubeService_GetPlayerInfoIsCached(t *testing.T) {
+	calls := 0
+	ys := newStubService(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(watchPage(playablePlayer("OK", "SE"))))
+	})
+
+	for i := 0; i < 2; i++ {
+		info, err := ys.GetPlayerInfo("abc123")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if info.Status != "OK" || len(info.AvailableCountries) != 1 {
+			t.Errorf("Unexpected player info %+v", info)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected 1 watch page request, got %d", calls)
+	}
+}
+
+func TestPlayerResponse_AgeRestricted(t *testing.T) {
+	tests := []struct {
+		name       string
+		player     map[string]any
+		restricted bool
+	}{
+		{"Unrestricted", playablePlayer("OK"), false},
+		{
+			"Age gate",
+			map[string]any{"playabilityStatus": map[string]any{"status": "LOGIN_REQUIRED", "reason": "Sign in to confirm your age"}},
+			true,
+		},
+		{
+			"Legacy age gate",
+			map[string]any{"playabilityStatus": map[string]any{"status": "OK", "desktopLegacyAgeGateReason": 1}},
+			true,
+		},
+		{
+			"Not family safe",
+			map[string]any{
+				"playabilityStatus": map[string]any{"status": "OK"},
+				"microformat":       map[string]any{"playerMicroformatRenderer": map[string]any{"isFamilySafe": false}},
+			},
+			true,
+		},
+		{
+			"Private video",
+			map[string]any{"playabilityStatus": map[string]any{"status": "LOGIN_REQUIRED", "reason": "This video is private"}},
+			false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			player, err := extractPlayerResponse(watchPage(test.player))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if player.ageRestricted() != test.restricted {
+				t.Errorf("Expected ageRestricted=%v", test.restricted)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+type ProxyStrategy string
+
+const (
+	ProxyRoundRobin    ProxyStrategy = "round-robin"
+	ProxyLeastFailures ProxyStrategy = "least-failures"
+)
+
+func (s ProxyStrategy) Valid() bool {
+	return s == ProxyRoundRobin || s == ProxyLeastFailures
+}
+
+var ErrNoHealthyProxies = errors.New("no healthy proxies available")
+
+// recentFailureDecay is how much of a proxy's recent failure count is kept
+// after each result, so that least-failures favours proxies that work now
+// over ones that merely failed less long ago.
+const recentFailureDecay = 0.8
+
+type Proxy struct {
+	URL    *url.URL
+	client *http.Client
+
+	consecutiveFailures int
+	totalFailures       int
+	recentFailures      float64
+	retired             bool
+	retryAt             time.Time
+}
+
+type ProxyStats struct {
+	URL                 string     `json:"url"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	TotalFailures       int        `json:"totalFailures"`
+	RecentFailures      float64    `json:"recentFailures"`
+	Retired             bool       `json:"retired"`
+	RetryAt             *time.Time `json:"retryAt,omitempty"`
+}
+
+// ProxyPool hands out proxies according to its strategy and retires a proxy
+// once it has failed maxFailures times in a row. A retired proxy is tried
+// again after retryAfter, on probation: a single failure retires it again.
+type ProxyPool struct {
+	proxies     []*Proxy
+	strategy    ProxyStrategy
+	maxFailures int
+	retryAfter  time.Duration
+	next        int
+	mutex       sync.Mutex
+
+	now func() time.Time
+}
+
+func NewProxyPool(rawURLs []string, strategy ProxyStrategy, maxFailures int, retryAfter, timeout time.Duration) (*ProxyPool, error) {
+	if !strategy.Valid() {
+		return nil, fmt.Errorf("unknown proxy strategy %q", strategy)
+	}
+	if maxFailures <= 0 {
+		maxFailures = defaultMaxProxyFailures
+	}
+	if retryAfter <= 0 {
+		retryAfter = defaultProxyRetryAfter
+	}
+
+	pool := &ProxyPool{
+		strategy:    strategy,
+		maxFailures: maxFailures,
+		retryAfter:  retryAfter,
+		now:         time.Now,
+	}
+
+	for _, rawURL := range rawURLs {
+		proxyURL, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", rawURL, err)
+		}
+
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q in %q", proxyURL.Scheme, rawURL)
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(proxyURL)
+
+		pool.proxies = append(pool.proxies, &Proxy{
+			URL:    proxyURL,
+			client: &http.Client{Transport: transport, Timeout: timeout},
+		})
+	}
+
+	return pool, nil
+}
+
+func (p *ProxyPool) Next() (*Proxy, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var chosen *Proxy
+	chosenIndex := -1
+
+	for i := range p.proxies {
+		index := (p.next + i) % len(p.proxies)
+		proxy := p.proxies[index]
+		if proxy.retired {
+			if p.now().Before(proxy.retryAt) {
+				continue
+			}
+			proxy.retired = false
+			proxy.consecutiveFailures = p.maxFailures - 1
+		}
+
+		if p.strategy == ProxyRoundRobin {
+			chosen, chosenIndex = proxy, index
+			break
+		}
+
+		if chosen == nil || proxy.recentFailures < chosen.recentFailures {
+			chosen, chosenIndex = proxy, index
+		}
+	}
+
+	if chosen == nil {
+		return nil, ErrNoHealthyProxies
+	}
+
+	p.next = (chosenIndex + 1) % len(p.proxies)
+	return chosen, nil
+}
+
+func (p *ProxyPool) ReportSuccess(proxy *Proxy) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	proxy.consecutiveFailures = 0
+	proxy.recentFailures *= recentFailureDecay
+}
+
+func (p *ProxyPool) ReportFailure(proxy *Proxy) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	proxy.consecutiveFailures++
+	proxy.totalFailures++
+	proxy.recentFailures = proxy.recentFailures*recentFailureDecay + 1
+	if proxy.consecutiveFailures >= p.maxFailures {
+		proxy.retired = true
+		proxy.retryAt = p.now().Add(p.retryAfter)
+	}
+}
+
+func (p *ProxyPool) Stats() []ProxyStats {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	stats := make([]ProxyStats, 0, len(p.proxies))
+	for _, proxy := range p.proxies {
+		proxyStats := ProxyStats{
+			URL:                 proxy.URL.Redacted(),
+			ConsecutiveFailures: proxy.consecutiveFailures,
+			TotalFailures:       proxy.totalFailures,
+			RecentFailures:      proxy.recentFailures,
+			Retired:             proxy.retired,
+		}
+		if proxy.retired {
+			retryAt := proxy.retryAt
+			proxyStats.RetryAt = &retryAt
+		}
+		stats = append(stats, proxyStats)
+	}
+	return stats
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProxyPool_RoundRobin(t *testing.T) {
+	pool, err := NewProxyPool([]string{"http://a:8080", "http://b:8080", "socks5://c:1080"}, ProxyRoundRobin, 3, 0, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"a:8080", "b:8080", "c:1080", "a:8080"}
+	for i, host := range expected {
+		proxy, err := pool.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if proxy.URL.Host != host {
+			t.Errorf("Expected proxy %s at call %d, got %s", host, i, proxy.URL.Host)
+		}
+	}
+}
+
+func TestProxyPool_LeastFailures(t *testing.T) {
+	pool, err := NewProxyPool([]string{"http://a:8080", "http://b:8080"}, ProxyLeastFailures, 10, 0, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	first, _ := pool.Next()
+	pool.ReportFailure(first)
+	pool.ReportSuccess(first)
+
+	for i := 0; i < 3; i++ {
+		proxy, _ := pool.Next()
+		if proxy.URL.Host != "b:8080" {
+			t.Errorf("Expected proxy with fewest failures, got %s", proxy.URL.Host)
+		}
+	}
+}
+
+func TestProxyPool_RetiresFailingProxies(t *testing.T) {
+	pool, err := NewProxyPool([]string{"http://a:8080", "http://b:8080"}, ProxyRoundRobin, 2, 0, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	a, _ := pool.Next()
+	pool.ReportFailure(a)
+	pool.ReportFailure(a)
+
+	for i := 0; i < 2; i++ {
+		proxy, _ := pool.Next()
+		if proxy.URL.Host != "b:8080" {
+			t.Errorf("Expected retired proxy to be skipped, got %s", proxy.URL.Host)
+		}
+		pool.ReportFailure(proxy)
+	}
+
+	if _, err := pool.Next(); !errors.Is(err, ErrNoHealthyProxies) {
+		t.Errorf("Expected ErrNoHealthyProxies, got %v", err)
+	}
+
+	stats := pool.Stats()
+	if len(stats) != 2 || !stats[0].Retired || !stats[1].Retired {
+		t.Errorf("Expected both proxies retired, got %+v", stats)
+	}
+}
+
+func TestProxyPool_LeastFailuresFavoursRecentResults(t *testing.T) {
+	pool, _ := NewProxyPool([]string{"http://a:8080", "http://b:8080"}, ProxyLeastFailures, 10, 0, 0)
+	a, b := pool.proxies[0], pool.proxies[1]
+
+	// a failed more often in total, but has worked ever since.
+	for i := 0; i < 3; i++ {
+		pool.ReportFailure(a)
+	}
+	for i := 0; i < 10; i++ {
+		pool.ReportSuccess(a)
+	}
+	pool.ReportFailure(b)
+
+	if proxy, _ := pool.Next(); proxy != a {
+		t.Errorf("Expected the proxy without recent failures, got %s", proxy.URL.Host)
+	}
+}
+
+func TestProxyPool_RetriesRetiredProxies(t *testing.T) {
+	pool, _ := NewProxyPool([]string{"http://a:8080"}, ProxyRoundRobin, 2, time.Minute, 0)
+	now := time.Now()
+	pool.now = func() time.Time { return now }
+
+	proxy, _ := pool.Next()
+	pool.ReportFailure(proxy)
+	pool.ReportFailure(proxy)
+
+	if _, err := pool.Next(); !errors.Is(err, ErrNoHealthyProxies) {
+		t.Fatalf("Expected ErrNoHealthyProxies while retired, got %v", err)
+	}
+	if stats := pool.Stats(); stats[0].RetryAt == nil || !stats[0].RetryAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("Expected a retry time a minute from now, got %+v", stats[0])
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := pool.Next(); err != nil {
+		t.Fatalf("Expected the proxy to be retried, got %v", err)
+	}
+
+	// On probation a single failure retires it again.
+	pool.ReportFailure(proxy)
+	if _, err := pool.Next(); !errors.Is(err, ErrNoHealthyProxies) {
+		t.Fatalf("Expected the proxy to be retired after failing on probation, got %v", err)
+	}
+
+	// A success ends probation.
+	now = now.Add(time.Minute)
+	pool.Next()
+	pool.ReportSuccess(proxy)
+	pool.ReportFailure(proxy)
+	if _, err := pool.Next(); err != nil {
+		t.Errorf("Expected the proxy to stay active after succeeding, got %v", err)
+	}
+}
+
+func TestProxyPool_SuccessResetsConsecutiveFailures(t *testing.T) {
+	pool, _ := NewProxyPool([]string{"http://a:8080"}, ProxyRoundRobin, 2, 0, 0)
+
+	proxy, _ := pool.Next()
+	pool.ReportFailure(proxy)
+	pool.ReportSuccess(proxy)
+	pool.ReportFailure(proxy)
+
+	if _, err := pool.Next(); err != nil {
+		t.Errorf("Expected proxy to remain active, got %v", err)
+	}
+
+	stats := pool.Stats()
+	if stats[0].TotalFailures != 2 || stats[0].ConsecutiveFailures != 1 {
+		t.Errorf("Unexpected stats %+v", stats[0])
+	}
+}
+
+func TestProxyPool_InvalidConfiguration(t *testing.T) {
+	if _, err := NewProxyPool([]string{"ftp://a:21"}, ProxyRoundRobin, 3, 0, 0); err == nil {
+		t.Error("Expected error for unsupported proxy scheme")
+	}
+	if _, err := NewProxyPool([]string{"http://a:8080"}, "random", 3, 0, 0); err == nil {
+		t.Error("Expected error for unknown strategy")
+	}
+}
+
+func TestYouTubeService_RoutesThroughProxy(t *testing.T) {
+	var proxiedURL string
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxiedURL = r.URL.String()
+		w.Write([]byte(`{"videoId":"proxied123"}`))
+	}))
+	defer proxyServer.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = "http://youtube.invalid"
+	config.Proxies = []string{proxyServer.URL}
+
+	ys, err := NewYouTubeServiceWithConfig(config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	videoIDs, err := ys.SearchVideos("Test", []string{"Artist"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if videoIDs[0] != "proxied123" {
+		t.Errorf("Expected proxied123, got %v", videoIDs)
+	}
+	if proxiedURL != "http://youtube.invalid/results?search_query=Test+Artist" {
+		t.Errorf("Expected request to be proxied, got %q", proxiedURL)
+	}
+}
+
+func TestYouTubeService_ProxyFailureStatusIsReported(t *testing.T) {
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer proxyServer.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = "http://youtube.invalid"
+	config.Proxies = []string{proxyServer.URL}
+	config.MaxProxyFailures = 1
+
+	ys, _ := NewYouTubeServiceWithConfig(config)
+
+	if _, err := ys.SearchVideos("Test", nil); err == nil {
+		t.Error("Expected error for rate-limited proxy")
+	}
+	if _, err := ys.SearchVideos("Other", nil); !errors.Is(err, ErrNoHealthyProxies) {
+		t.Errorf("Expected ErrNoHealthyProxies after retirement, got %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"time"
+)
+
+// maxCandidateChecks bounds how many watch pages a single resolution may
+// fetch while looking for a usable candidate.
+const maxCandidateChecks = 5
+
+type Candidate struct {
+	ID            string        `json:"id"`
+	Title         string        `json:"title,omitempty"`
+	Channel       string        `json:"channel,omitempty"`
+	Duration      int           `json:"duration,omitempty"`
+	Kind          string        `json:"kind,omitempty"`
+	Similarity    *float64      `json:"similarity,omitempty"`
+	Confidence    *float64      `json:"confidence,omitempty"`
+	Availability  *Availability `json:"availability,omitempty"`
+	Embeddable    *bool         `json:"embeddable,omitempty"`
+	AgeRestricted *bool         `json:"ageRestricted,omitempty"`
+
+	score float64
+}
+
+type SkippedCandidate struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// Resolution is the outcome of Resolve. When Video is nil, Reason explains
+// why, and LowConfidence lists the candidates that fell below the minimum
+// confidence so they can be reviewed by hand. Audio is the best matching
+// song of a YouTube Music search, usually an art track.
+type Resolution struct {
+	Video         *Candidate
+	Audio         *Candidate
+	Reason        string
+	Candidates    []*Candidate
+	LowConfidence []*Candidate
+	Skipped       []SkippedCandidate
+}
+
+// Resolve searches for the song and picks the best candidate that satisfies
+// opts. Video is nil when no candidate qualifies.
+func (ys *YouTubeService) Resolve(title string, artists []string, opts SearchOptions) (*Resolution, error) {
+	opts = ys.withDefaults(opts)
+
+	results, songs, err := ys.search(title, artists, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	credits := ParseCredits(title, artists)
+	resolution := &Resolution{}
+	resolution.Audio = ys.bestSong(credits, songs, opts, resolution)
+	candidates := ys.rankCandidates(credits, results, opts, resolution)
+	resolution.Candidates = candidates
+
+	if minConfidence := *opts.MinConfidence; minConfidence > 0 {
+		var confident []*Candidate
+		for _, candidate := range candidates {
+			if candidate.Confidence != nil && *candidate.Confidence >= minConfidence {
+				confident = append(confident, candidate)
+			} else {
+				resolution.LowConfidence = append(resolution.LowConfidence, candidate)
+			}
+		}
+		candidates = confident
+
+		if len(candidates) == 0 {
+			resolution.Reason = fmt.Sprintf("No candidate reached the minimum confidence of %g.", minConfidence)
+			return resolution, nil
+		}
+	}
+
+	if !opts.needsPlayerInfo() {
+		if len(candidates) > 0 {
+			resolution.Video = candidates[0]
+		} else {
+			resolution.Reason = "No candidate matched the search."
+		}
+		return resolution, nil
+	}
+
+	for i, candidate := range candidates {
+		if i >= maxCandidateChecks {
+			break
+		}
+
+		info, err := ys.GetPlayerInfo(candidate.ID)
+		if err != nil {
+			log.Printf("Failed to fetch player info for %s: %v", candidate.ID, err)
+			if opts.filters() {
+				resolution.Skipped = append(resolution.Skipped, SkippedCandidate{ID: candidate.ID, Reason: "player info unavailable"})
+				continue
+			}
+			resolution.Video = candidate
+			return resolution, nil
+		}
+
+		candidate.Availability = info.AvailabilityIn(opts.Region)
+		candidate.Embeddable = info.Embeddable
+		candidate.AgeRestricted = &info.AgeRestricted
+
+		if reason := rejectionReason(candidate, opts); reason != "" {
+			resolution.Skipped = append(resolution.Skipped, SkippedCandidate{ID: candidate.ID, Reason: reason})
+			continue
+		}
+
+		resolution.Video = candidate
+		return resolution, nil
+	}
+
+	resolution.Reason = "No candidate satisfied the requested filters."
+	return resolution, nil
+}
+
+// rankCandidates orders results by title and artist similarity and by how
+// well they match opts. Official videos win ties, and YouTube's order is
+// kept among otherwise equally good matches. Candidates excluded outright
+// are recorded in resolution.Skipped.
+func (ys *YouTubeService) rankCandidates(credits Credits, results []SearchResult, opts SearchOptions, resolution *Resolution) []*Candidate {
+	candidates := make([]*Candidate, 0, len(results))
+
+	for _, result := range results {
+		if slices.Contains(opts.ExcludeIDs, result.ID) {
+			resolution.Skipped = append(resolution.Skipped, SkippedCandidate{
+				ID:     result.ID,
+				Reason: "The video is already used for another track.",
+			})
+			continue
+		}
+
+		candidate := &Candidate{
+			ID:       result.ID,
+			Title:    result.Title,
+			Channel:  result.Channel,
+			Duration: result.Duration,
+			Kind:     result.Kind,
+			score:    1,
+		}
+
+		if result.Title != "" {
+			similarity := math.Round(matchSimilarity(credits, result)*1000) / 1000
+			candidate.Similarity = &similarity
+			candidate.score = similarity
+		}
+
+		if opts.Duration > 0 {
+			actual := time.Duration(result.Duration) * time.Second
+			fit := durationScore(actual, opts.Duration, *opts.DurationTolerance)
+			candidate.score *= fit
+
+			if opts.StrictDuration && result.Duration > 0 && fit < 1 {
+				resolution.Skipped = append(resolution.Skipped, SkippedCandidate{
+					ID:     result.ID,
+					Reason: fmt.Sprintf("The duration %s is outside the tolerance.", formatDuration(actual)),
+				})
+				continue
+			}
+		}
+
+		if candidate.Similarity != nil {
+			confidence := math.Round(candidate.score*1000) / 1000
+			candidate.Confidence = &confidence
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	// Official videos win ties, such as with a fan upload of the same title.
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].Kind == KindOfficialVideo && candidates[j].Kind != KindOfficialVideo
+	})
+
+	return candidates
+}
+
+// bestSong returns the song that best matches credits, or nil when there is
+// none or it doesn't reach the minimum confidence. Songs excluded outright
+// are recorded in resolution.Skipped along with the videos. Songs aren't
+// checked against the availability filters.
+func (ys *YouTubeService) bestSong(credits Credits, songs []SearchResult, opts SearchOptions, resolution *Resolution) *Candidate {
+	ranked := ys.rankCandidates(credits, songs, opts, resolution)
+	if len(ranked) == 0 {
+		return nil
+	}
+
+	best := ranked[0]
+	if minConfidence := *opts.MinConfidence; minConfidence > 0 && (best.Confidence == nil || *best.Confidence < minConfidence) {
+		return nil
+	}
+	return best
+}
+
+func (opts SearchOptions) filters() bool {
+	return opts.RequirePlayable || opts.RequireEmbeddable || opts.ExcludeAgeRestricted
+}
+
+func (opts SearchOptions) needsPlayerInfo() bool {
+	return opts.CheckAvailability || opts.filters()
+}
+
+// rejectionReason explains why candidate doesn't satisfy opts, or returns
+// an empty string when it does.
+func rejectionReason(candidate *Candidate, opts SearchOptions) string {
+	availability := candidate.Availability
+
+	if opts.RequirePlayable && !availability.Playable {
+		if availability.Reason != "" {
+			return availability.Reason
+		}
+		return "The video is not playable (" + availability.Status + ")."
+	}
+
+	// Without a playableInEmbed flag, the video is given the benefit of
+	// the doubt rather than skipped.
+	if opts.RequireEmbeddable && candidate.Embeddable != nil && !*candidate.Embeddable {
+		return "The video can't be embedded."
+	}
+
+	if opts.ExcludeAgeRestricted && *candidate.AgeRestricted {
+		return "The video is age-restricted."
+	}
+
+	return ""
+}
@@ -0,0 +1,353 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newStubService(t *testing.T, handler http.HandlerFunc) *YouTubeService {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	config.MusicBaseURL = server.URL
+	config.ThumbnailBaseURL = server.URL
+	config.ThumbnailCacheDir = t.TempDir()
+	ys, err := NewYouTubeServiceWithConfig(config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return ys
+}
+
+// stubYouTube serves searchHTML for /results and the matching entry of
+// players for /watch.
+func stubYouTube(searchHTML string, players map[string]map[string]any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/watch") {
+			player, ok := players[r.URL.Query().Get("v")]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(watchPage(player)))
+			return
+		}
+		w.Write([]byte(searchHTML))
+	}
+}
+
+func TestYouTubeService_ResolveFirstCandidate(t *testing.T) {
+	calls := 0
+	ys := newStubService(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte(`{"videoId":"first"} {"videoId":"second"}`))
+	})
+
+	resolution, err := ys.Resolve("Test", nil, SearchOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resolution.Video == nil || resolution.Video.ID != "first" || resolution.Video.Availability != nil {
+		t.Errorf("Expected first candidate without availability, got %+v", resolution.Video)
+	}
+	if calls != 1 {
+		t.Errorf("Expected no watch page requests, got %d requests", calls)
+	}
+}
+
+func TestYouTubeService_ResolveSkipsRegionBlocked(t *testing.T) {
+	ys := newStubService(t, stubYouTube(`{"videoId":"blocked"} {"videoId":"gone"} {"videoId":"playable"}`, map[string]map[string]any{
+		"blocked":  playablePlayer("OK", "SE"),
+		"gone":     {"playabilityStatus": map[string]any{"status": "ERROR", "reason": "Video unavailable"}},
+		"playable": playablePlayer("OK", "SE", "US"),
+	}))
+
+	resolution, err := ys.Resolve("Test", nil, SearchOptions{Region: "US", RequirePlayable: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resolution.Video == nil || resolution.Video.ID != "playable" {
+		t.Fatalf("Expected playable candidate, got %+v", resolution.Video)
+	}
+	if !resolution.Video.Availability.Playable || resolution.Video.Availability.Region != "US" {
+		t.Errorf("Unexpected availability %+v", resolution.Video.Availability)
+	}
+	if len(resolution.Skipped) != 2 || resolution.Skipped[0].ID != "blocked" || resolution.Skipped[1].Reason != "Video unavailable" {
+		t.Errorf("Unexpected skipped candidates %+v", resolution.Skipped)
+	}
+}
+
+func TestYouTubeService_ResolveReportsAvailabilityWithoutSkipping(t *testing.T) {
+	ys := newStubService(t, stubYouTube(`{"videoId":"blocked"} {"videoId":"playable"}`, map[string]map[string]any{
+		"blocked":  playablePlayer("OK", "SE"),
+		"playable": playablePlayer("OK"),
+	}))
+
+	resolution, err := ys.Resolve("Test", nil, SearchOptions{Region: "US", CheckAvailability: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resolution.Video == nil || resolution.Video.ID != "blocked" {
+		t.Fatalf("Expected first candidate, got %+v", resolution.Video)
+	}
+	if resolution.Video.Availability.Playable {
+		t.Errorf("Expected availability to report the block, got %+v", resolution.Video.Availability)
+	}
+}
+
+func TestYouTubeService_ResolveNothingPlayable(t *testing.T) {
+	ys := newStubService(t, stubYouTube(`{"videoId":"missing"}`, nil))
+
+	resolution, err := ys.Resolve("Test", nil, SearchOptions{RequirePlayable: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resolution.Video != nil {
+		t.Errorf("Expected no video, got %+v", resolution.Video)
+	}
+	if len(resolution.Skipped) != 1 {
+		t.Errorf("Expected 1 skipped candidate, got %+v", resolution.Skipped)
+	}
+}
+
+func TestYouTubeService_ResolveSkipsNonEmbeddable(t *testing.T) {
+	label := playablePlayer("OK")
+	label["playabilityStatus"] = map[string]any{"status": "OK", "playableInEmbed": false}
+	embeddable := playablePlayer("OK")
+	embeddable["playabilityStatus"] = map[string]any{"status": "OK", "playableInEmbed": true}
+
+	ys := newStubService(t, stubYouTube(`{"videoId":"label"} {"videoId":"embeddable"}`, map[string]map[string]any{
+		"label":      label,
+		"embeddable": embeddable,
+	}))
+
+	resolution, err := ys.Resolve("Test", nil, SearchOptions{RequireEmbeddable: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resolution.Video == nil || resolution.Video.ID != "embeddable" {
+		t.Fatalf("Expected embeddable candidate, got %+v", resolution.Video)
+	}
+	if !*resolution.Video.Embeddable {
+		t.Error("Expected chosen video to be reported as embeddable")
+	}
+	if len(resolution.Skipped) != 1 || resolution.Skipped[0].ID != "label" {
+		t.Errorf("Expected label upload to be skipped, got %+v", resolution.Skipped)
+	}
+}
+
+func TestYouTubeService_ResolveUnknownEmbeddability(t *testing.T) {
+	// The player response doesn't say whether the video can be embedded.
+	ys := newStubService(t, stubYouTube(`{"videoId":"unknown"}`, map[string]map[string]any{
+		"unknown": playablePlayer("OK"),
+	}))
+
+	resolution, err := ys.Resolve("Test", nil, SearchOptions{RequireEmbeddable: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resolution.Video == nil || resolution.Video.ID != "unknown" {
+		t.Fatalf("Expected the video to be kept, got %+v (skipped %+v)", resolution.Video, resolution.Skipped)
+	}
+	if resolution.Video.Embeddable != nil {
+		t.Errorf("Expected embeddability to be reported as unknown, got %v", *resolution.Video.Embeddable)
+	}
+}
+
+func TestYouTubeService_ResolveSkipsAgeRestricted(t *testing.T) {
+	ys := newStubService(t, stubYouTube(`{"videoId":"gated"} {"videoId":"family"}`, map[string]map[string]any{
+		"gated":  {"playabilityStatus": map[string]any{"status": "LOGIN_REQUIRED", "reason": "Sign in to confirm your age"}},
+		"family": playablePlayer("OK"),
+	}))
+
+	resolution, err := ys.Resolve("Test", nil, SearchOptions{ExcludeAgeRestricted: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resolution.Video == nil || resolution.Video.ID != "family" {
+		t.Fatalf("Expected family-safe candidate, got %+v", resolution.Video)
+	}
+	if *resolution.Video.AgeRestricted {
+		t.Error("Expected chosen video not to be age-restricted")
+	}
+	if len(resolution.Skipped) != 1 || resolution.Skipped[0].Reason != "The video is age-restricted." {
+		t.Errorf("Unexpected skipped candidates %+v", resolution.Skipped)
+	}
+}
+
+func TestYouTubeService_ResolvePrefersMatchingDuration(t *testing.T) {
+	ys := newStubService(t, stubYouTube(searchPage(
+		videoRenderer("extended", "Song (Extended Mix)", "Artist", "7:30"),
+		videoRenderer("loop", "Song 10 hours", "Loops", "10:00:00"),
+		videoRenderer("single", "Song", "Artist", "3:32"),
+	), nil))
+
+	resolution, err := ys.Resolve("Song", []string{"Artist"}, SearchOptions{Duration: 210 * time.Second})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resolution.Video == nil || resolution.Video.ID != "single" {
+		t.Fatalf("Expected single, got %+v", resolution.Video)
+	}
+	if resolution.Video.Duration != 212 || resolution.Video.Title != "Song" {
+		t.Errorf("Expected candidate metadata, got %+v", resolution.Video)
+	}
+	if len(resolution.Skipped) != 0 {
+		t.Errorf("Expected no skipped candidates without strict matching, got %+v", resolution.Skipped)
+	}
+}
+
+func TestYouTubeService_ResolveStrictDuration(t *testing.T) {
+	ys := newStubService(t, stubYouTube(searchPage(
+		videoRenderer("extended", "Song (Extended Mix)", "Artist", "7:30"),
+		videoRenderer("loop", "Song 10 hours", "Loops", "10:00:00"),
+	), nil))
+
+	tolerance := 5 * time.Second
+	resolution, err := ys.Resolve("Song", []string{"Artist"}, SearchOptions{
+		Duration:          210 * time.Second,
+		DurationTolerance: &tolerance,
+		StrictDuration:    true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resolution.Video != nil {
+		t.Errorf("Expected no video, got %+v", resolution.Video)
+	}
+	if len(resolution.Skipped) != 2 || resolution.Skipped[0].Reason != "The duration 7:30 is outside the tolerance." {
+		t.Errorf("Unexpected skipped candidates %+v", resolution.Skipped)
+	}
+}
+
+func TestYouTubeService_ResolveZeroDurationTolerance(t *testing.T) {
+	ys := newStubService(t, stubYouTube(searchPage(
+		videoRenderer("longer", "Artist - Song", "Artist", "3:31"),
+		videoRenderer("exact", "Artist - Song (Audio)", "Artist", "3:30"),
+	), nil))
+
+	exact := time.Duration(0)
+	resolution, err := ys.Resolve("Song", []string{"Artist"}, SearchOptions{
+		Duration:          210 * time.Second,
+		DurationTolerance: &exact,
+		StrictDuration:    true,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resolution.Video == nil || resolution.Video.ID != "exact" {
+		t.Errorf("Expected the exact-length video, got %+v", resolution.Video)
+	}
+	if len(resolution.Skipped) != 1 || resolution.Skipped[0].ID != "longer" {
+		t.Errorf("Expected the video a second too long to be skipped, got %+v", resolution.Skipped)
+	}
+}
+
+func TestYouTubeService_ResolveRanksBySimilarity(t *testing.T) {
+	ys := newStubService(t, stubYouTube(searchPage(
+		videoRenderer("reaction", "Reacting to random songs", "Reactor", "12:00"),
+		videoRenderer("official", "Loreen - Euphoria", "Loreen", "3:04"),
+	), nil))
+
+	resolution, err := ys.Resolve("Euphoria", []string{"Loreen"}, SearchOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resolution.Video == nil || resolution.Video.ID != "official" {
+		t.Fatalf("Expected official upload, got %+v", resolution.Video)
+	}
+	if len(resolution.Candidates) != 2 || *resolution.Candidates[0].Similarity != 1 {
+		t.Errorf("Expected official upload to have similarity 1, got %+v", resolution.Candidates)
+	}
+}
+
+func TestYouTubeService_ResolveMinConfidence(t *testing.T) {
+	ys := newStubService(t, stubYouTube(searchPage(
+		videoRenderer("reaction", "Reacting to random songs", "Reactor", "12:00"),
+		videoRenderer("official", "Loreen - Euphoria", "Loreen", "3:04"),
+	), nil))
+
+	minConfidence := 0.8
+	resolution, err := ys.Resolve("Euphoria", []string{"Loreen"}, SearchOptions{MinConfidence: &minConfidence})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resolution.Video == nil || resolution.Video.ID != "official" {
+		t.Fatalf("Expected official upload, got %+v", resolution.Video)
+	}
+	if len(resolution.LowConfidence) != 1 || resolution.LowConfidence[0].ID != "reaction" {
+		t.Errorf("Expected reaction video to be low confidence, got %+v", resolution.LowConfidence)
+	}
+
+	resolution, err = ys.Resolve("Gruppa Krovi", []string{"Kino"}, SearchOptions{MinConfidence: &minConfidence})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resolution.Video != nil {
+		t.Errorf("Expected no video, got %+v", resolution.Video)
+	}
+	if resolution.Reason != "No candidate reached the minimum confidence of 0.8." {
+		t.Errorf("Unexpected reason %q", resolution.Reason)
+	}
+	if len(resolution.LowConfidence) != 2 {
+		t.Errorf("Expected 2 low-confidence candidates, got %+v", resolution.LowConfidence)
+	}
+}
+
+func TestYouTubeService_ResolveDefaultMinConfidence(t *testing.T) {
+	ys := newStubService(t, stubYouTube(searchPage(
+		videoRenderer("reaction", "Reacting to random songs", "Reactor", "12:00"),
+	), nil))
+	ys.config.MinConfidence = 0.5
+
+	resolution, err := ys.Resolve("Euphoria", []string{"Loreen"}, SearchOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resolution.Video != nil {
+		t.Errorf("Expected configured minimum to reject the candidate, got %+v", resolution.Video)
+	}
+
+	disabled := 0.0
+	resolution, err = ys.Resolve("Euphoria", []string{"Loreen"}, SearchOptions{MinConfidence: &disabled})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resolution.Video == nil {
+		t.Errorf("Expected min_confidence 0 to disable the check")
+	}
+}
+
+func TestYouTubeService_ResolveExcludeIDs(t *testing.T) {
+	ys := newStubService(t, stubYouTube(searchPage(
+		videoRenderer("first", "Loreen - Euphoria", "Loreen", "3:04"),
+		videoRenderer("second", "Loreen - Euphoria (Live)", "Loreen", "3:10"),
+	), nil))
+
+	resolution, err := ys.Resolve("Euphoria", []string{"Loreen"}, SearchOptions{ExcludeIDs: []string{"first"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resolution.Video == nil || resolution.Video.ID != "second" {
+		t.Errorf("Expected the excluded video to be passed over, got %+v", resolution.Video)
+	}
+	if len(resolution.Skipped) != 1 || resolution.Skipped[0].ID != "first" {
+		t.Errorf("Expected the excluded video to be listed as skipped, got %+v", resolution.Skipped)
+	}
+}
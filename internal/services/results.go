@@ -0,0 +1,130 @@
+package services
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// maxSearchResults matches the number of IDs extractVideoIDs returns.
+const maxSearchResults = 10
+
+var initialDataMarkers = []string{"var ytInitialData = ", `window["ytInitialData"] = `}
+
+// SearchResult is a video listed on a search results page.
+type SearchResult struct {
+	ID       string `json:"id"`
+	Title    string `json:"title,omitempty"`
+	Channel  string `json:"channel,omitempty"`
+	Duration int    `json:"duration,omitempty"` // seconds, 0 when unknown
+	// Kind is one of the Kind constants for YouTube Music results.
+	Kind string `json:"kind,omitempty"`
+}
+
+// extractSearchResults parses the videoRenderer entries out of the page's
+// ytInitialData. It returns nil when the page has no such data.
+func extractSearchResults(html string) []SearchResult {
+	data, ok := extractJSONAfter(html, initialDataMarkers...)
+	if !ok {
+		return nil
+	}
+
+	var results []SearchResult
+	seen := make(map[string]bool)
+
+	walkJSON(data, func(key string, value any) bool {
+		if len(results) >= maxSearchResults {
+			return false
+		}
+		if key != "videoRenderer" {
+			return true
+		}
+
+		renderer, ok := value.(map[string]any)
+		if !ok {
+			return true
+		}
+
+		result := SearchResult{
+			ID:      stringAt(renderer, "videoId"),
+			Title:   textAt(renderer, "title"),
+			Channel: textAt(renderer, "ownerText"),
+		}
+		if length := textAt(renderer, "lengthText"); length != "" {
+			if duration, err := ParseDuration(length); err == nil {
+				result.Duration = int(duration.Seconds())
+			}
+		}
+
+		if result.ID != "" && !seen[result.ID] {
+			seen[result.ID] = true
+			results = append(results, result)
+		}
+		return false
+	})
+
+	return results
+}
+
+// extractJSONAfter decodes the JSON value that follows the first of markers
+// found in html.
+func extractJSONAfter(html string, markers ...string) (any, bool) {
+	for _, marker := range markers {
+		index := strings.Index(html, marker)
+		if index == -1 {
+			continue
+		}
+
+		var data any
+		decoder := json.NewDecoder(strings.NewReader(html[index+len(marker):]))
+		if err := decoder.Decode(&data); err == nil {
+			return data, true
+		}
+	}
+	return nil, false
+}
+
+// walkJSON visits every object member in value depth-first, in document
+// order for arrays and key order for objects. Returning false from visit
+// skips that member's children.
+func walkJSON(value any, visit func(key string, value any) bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			if visit(key, v[key]) {
+				walkJSON(v[key], visit)
+			}
+		}
+	case []any:
+		for _, child := range v {
+			walkJSON(child, visit)
+		}
+	}
+}
+
+func stringAt(object map[string]any, key string) string {
+	s, _ := object[key].(string)
+	return s
+}
+
+// textAt reads YouTube's text objects, which are either {"simpleText": ...}
+// or {"runs": [{"text": ...}, ...]}.
+func textAt(object map[string]any, key string) string {
+	text, ok := object[key].(map[string]any)
+	if !ok {
+		return ""
+	}
+	if simple, ok := text["simpleText"].(string); ok {
+		return simple
+	}
+
+	runs, _ := text["runs"].([]any)
+	var b strings.Builder
+	for _, run := range runs {
+		if r, ok := run.(map[string]any); ok {
+			b.WriteString(stringAt(r, "text"))
+		}
+	}
+	return b.String()
+}
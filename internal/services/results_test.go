@@ -0,0 +1,89 @@
+package services
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func videoRenderer(id, title, channel, length string) map[string]any {
+	renderer := map[string]any{
+		"videoId":   id,
+		"title":     map[string]any{"runs": []any{map[string]any{"text": title}}},
+		"ownerText": map[string]any{"runs": []any{map[string]any{"text": channel}}},
+	}
+	if length != "" {
+		renderer["lengthText"] = map[string]any{"simpleText": length}
+	}
+	return map[string]any{"videoRenderer": renderer}
+}
+
+// searchPage renders a minimal results page around the given renderers.
+func searchPage(renderers ...map[string]any) string {
+	contents := make([]any, 0, len(renderers))
+	for _, renderer := range renderers {
+		contents = append(contents, renderer)
+	}
+	data := map[string]any{
+		"contents": map[string]any{
+			"twoColumnSearchResultsRenderer": map[string]any{
+				"primaryContents": map[string]any{
+					"sectionListRenderer": map[string]any{
+						"contents": []any{
+							map[string]any{"itemSectionRenderer": map[string]any{"contents": contents}},
+						},
+					},
+				},
+			},
+		},
+	}
+	encoded, _ := json.Marshal(data)
+	return `<script>var ytInitialData = ` + string(encoded) + `;</script>`
+}
+
+func TestExtractSearchResults(t *testing.T) {
+	html := searchPage(
+		videoRenderer("abc", "Loreen - Euphoria", "Loreen", "3:04"),
+		videoRenderer("def", "Euphoria (10 hours)", "Loops", "10:00:00"),
+		videoRenderer("abc", "Loreen - Euphoria", "Loreen", "3:04"),
+		videoRenderer("ghi", "Euphoria live", "SVT", ""),
+	)
+
+	results := extractSearchResults(html)
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 unique results, got %+v", results)
+	}
+
+	expected := SearchResult{ID: "abc", Title: "Loreen - Euphoria", Channel: "Loreen", Duration: 184}
+	if results[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, results[0])
+	}
+	if results[1].Duration != 36000 {
+		t.Errorf("Expected 36000 seconds, got %d", results[1].Duration)
+	}
+	if results[2].ID != "ghi" || results[2].Duration != 0 {
+		t.Errorf("Expected unknown duration for ghi, got %+v", results[2])
+	}
+}
+
+func TestExtractSearchResults_NoInitialData(t *testing.T) {
+	if results := extractSearchResults(`{"videoId":"abc"}`); results != nil {
+		t.Errorf("Expected nil without ytInitialData, got %+v", results)
+	}
+}
+
+func TestTextAt(t *testing.T) {
+	object := map[string]any{
+		"simple": map[string]any{"simpleText": "Simple"},
+		"runs":   map[string]any{"runs": []any{map[string]any{"text": "A"}, map[string]any{"text": "B"}}},
+	}
+
+	if result := textAt(object, "simple"); result != "Simple" {
+		t.Errorf("Expected Simple, got %q", result)
+	}
+	if result := textAt(object, "runs"); result != "AB" {
+		t.Errorf("Expected AB, got %q", result)
+	}
+	if result := textAt(object, "missing"); result != "" {
+		t.Errorf("Expected empty text, got %q", result)
+	}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"slices"
+	"strings"
+)
+
+// Weights for combining the title and artist similarity of a candidate.
+const (
+	titleWeight  = 0.7
+	artistWeight = 0.3
+)
+
+// Tokens at least fuzzyTokenMinLength runes long whose Jaro-Winkler
+// similarity reaches fuzzyTokenThreshold are treated as the same word.
+const (
+	fuzzyTokenMinLength = 4
+	fuzzyTokenThreshold = 0.92
+)
+
+// channelDecorations are suffixes YouTube and labels add to channel names.
+var channelDecorations = strings.NewReplacer(" - Topic", "", "VEVO", "", "Vevo", "")
+
+// Similarity compares two strings after normalization, returning a value
+// between 0 and 1. It is a token-set ratio, which ignores word order and
+// extra words, in which tokens within a small Jaro-Winkler distance of each
+// other count as shared, so minor misspellings still match.
+func Similarity(a, b string) float64 {
+	a, b = NormalizeText(a), NormalizeText(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	return tokenSetRatio(a, b)
+}
+
+// TransliteratedSimilarity is Similarity over the best pairing of the
+// original and romanized forms of a and b.
+func TransliteratedSimilarity(a, b string) float64 {
+	best := 0.0
+	for _, x := range []string{a, Transliterate(a)} {
+		for _, y := range []string{b, Transliterate(b)} {
+			best = max(best, Similarity(x, y))
+		}
+	}
+	return best
+}
+
+// matchSimilarity rates how well a search result matches the requested
+// song. Titles are compared without decorations; artists are compared with
+// both the channel name and the video title, since uploads are often titled
+// "Artist - Song".
+func matchSimilarity(credits Credits, result SearchResult) float64 {
+	titleScore := TransliteratedSimilarity(StripTitleDecorations(credits.Title), StripTitleDecorations(result.Title))
+
+	artists := credits.Artists()
+	if len(artists) == 0 {
+		return titleScore
+	}
+
+	joined := strings.Join(artists, " ")
+	channel := channelDecorations.Replace(result.Channel)
+	artistScore := TransliteratedSimilarity(joined, channel)
+	for _, form := range []string{result.Title, Transliterate(result.Title)} {
+		for _, artist := range []string{joined, Transliterate(joined)} {
+			artistScore = max(artistScore, tokenContainment(NormalizeText(artist), NormalizeText(form)))
+		}
+	}
+
+	return titleWeight*titleScore + artistWeight*artistScore
+}
+
+// tokenSetRatio follows the classic fuzzy-matching definition: the shared
+// tokens are compared against each side's full token set, so "euphoria"
+// fully matches "loreen euphoria official video".
+func tokenSetRatio(a, b string) float64 {
+	tokensB := uniqueTokens(b)
+	tokensA := uniqueTokens(alignTokens(a, tokensB))
+
+	var shared, onlyA, onlyB []string
+	for _, token := range tokensA {
+		if slices.Contains(tokensB, token) {
+			shared = append(shared, token)
+		} else {
+			onlyA = append(onlyA, token)
+		}
+	}
+	for _, token := range tokensB {
+		if !slices.Contains(tokensA, token) {
+			onlyB = append(onlyB, token)
+		}
+	}
+
+	base := strings.Join(shared, " ")
+	withA := strings.TrimSpace(base + " " + strings.Join(onlyA, " "))
+	withB := strings.TrimSpace(base + " " + strings.Join(onlyB, " "))
+
+	return max(ratio(base, withA), ratio(base, withB), ratio(withA, withB))
+}
+
+// alignTokens rewrites tokens of s that are near-identical to one of
+// targets into that target's spelling.
+func alignTokens(s string, targets []string) string {
+	tokens := strings.Fields(s)
+	for i, token := range tokens {
+		if len([]rune(token)) < fuzzyTokenMinLength || slices.Contains(targets, token) {
+			continue
+		}
+		for _, target := range targets {
+			if len([]rune(target)) >= fuzzyTokenMinLength && jaroWinkler(token, target) >= fuzzyTokenThreshold {
+				tokens[i] = target
+				break
+			}
+		}
+	}
+	return strings.Join(tokens, " ")
+}
+
+// tokenContainment is the share of a's tokens that appear in b.
+func tokenContainment(a, b string) float64 {
+	tokensA := uniqueTokens(a)
+	if len(tokensA) == 0 {
+		return 0
+	}
+	tokensB := uniqueTokens(b)
+
+	found := 0
+	for _, token := range tokensA {
+		if slices.Contains(tokensB, token) {
+			found++
+		}
+	}
+	return float64(found) / float64(len(tokensA))
+}
+
+func uniqueTokens(s string) []string {
+	tokens := strings.Fields(s)
+	slices.Sort(tokens)
+	return slices.Compact(tokens)
+}
+
+// ratio is the indel similarity 2*LCS / (len(a)+len(b)) over runes.
+func ratio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra)+len(rb) == 0 {
+		return 0
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			if ra[i-1] == rb[j-1] {
+				current[j] = previous[j-1] + 1
+			} else {
+				current[j] = max(previous[j], current[j-1])
+			}
+		}
+		previous, current = current, previous
+	}
+
+	return 2 * float64(previous[len(rb)]) / float64(len(ra)+len(rb))
+}
+
+func jaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(max(len(ra), len(rb))/2-1, 0)
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		start := max(0, i-window)
+		end := min(len(rb), i+window+1)
+		for j := start; j < end; j++ {
+			if !matchedB[j] && ra[i] == rb[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTokenSetRatio(t *testing.T) {
+	if result := tokenSetRatio("euphoria", "loreen euphoria official video"); result != 1 {
+		t.Errorf("Expected subset to match fully, got %v", result)
+	}
+	if result := tokenSetRatio("hello world", "world hello"); result != 1 {
+		t.Errorf("Expected word order to be ignored, got %v", result)
+	}
+	if result := tokenSetRatio("euphoria", "bohemian rhapsody"); result > 0.5 {
+		t.Errorf("Expected unrelated strings to score low, got %v", result)
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"martha", "marhta", 0.961},
+		{"dwayne", "duane", 0.84},
+		{"dixon", "dicksonx", 0.813},
+		{"same", "same", 1},
+		{"abc", "xyz", 0},
+	}
+
+	for _, test := range tests {
+		result := jaroWinkler(test.a, test.b)
+		if math.Abs(result-test.expected) > 0.001 {
+			t.Errorf("jaroWinkler(%q, %q) = %.3f; want %.3f", test.a, test.b, result, test.expected)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	if result := Similarity("Euphoria", "EUPHORIA"); result != 1 {
+		t.Errorf("Expected identical normalized strings to score 1, got %v", result)
+	}
+	if result := Similarity("", "Euphoria"); result != 0 {
+		t.Errorf("Expected empty string to score 0, got %v", result)
+	}
+
+	if result := Similarity("Euphoria", "Euphorla"); result != 1 {
+		t.Errorf("Expected a one-letter typo to match, got %v", result)
+	}
+
+	close := Similarity("Euphoria", "Euphoria Remix")
+	far := Similarity("Euphoria", "Bohemian Rhapsody")
+	if close <= far {
+		t.Errorf("Expected a typo to score higher than an unrelated title, got %v and %v", close, far)
+	}
+}
+
+func TestTransliteratedSimilarity(t *testing.T) {
+	if result := TransliteratedSimilarity("Кино", "Kino"); result != 1 {
+		t.Errorf("Expected Cyrillic to match its romanization, got %v", result)
+	}
+	if result := TransliteratedSimilarity("강남스타일", "Gangnam Seutail"); result < 0.9 {
+		t.Errorf("Expected Hangul to match its romanization, got %v", result)
+	}
+	if result := Similarity("Кино", "Kino"); result != 0 {
+		t.Errorf("Expected plain similarity not to transliterate, got %v", result)
+	}
+}
+
+func TestMatchSimilarity(t *testing.T) {
+	credits := ParseCredits("Euphoria", []string{"Loreen"})
+
+	official := matchSimilarity(credits, SearchResult{Title: "Loreen - Euphoria (Official Video)", Channel: "LoreenVEVO"})
+	cover := matchSimilarity(credits, SearchResult{Title: "Euphoria cover", Channel: "Someone Else"})
+	unrelated := matchSimilarity(credits, SearchResult{Title: "Bohemian Rhapsody", Channel: "Queen Official"})
+
+	if official < 0.95 {
+		t.Errorf("Expected official upload to score high, got %v", official)
+	}
+	if !(official > cover && cover > unrelated) {
+		t.Errorf("Expected official > cover > unrelated, got %v, %v, %v", official, cover, unrelated)
+	}
+
+	romanized := matchSimilarity(ParseCredits("Группа крови", []string{"Кино"}), SearchResult{Title: "Kino - Gruppa krovi", Channel: "Kino"})
+	if romanized < 0.95 {
+		t.Errorf("Expected romanized upload to score high, got %v", romanized)
+	}
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSpotifyAPIBaseURL      = "https://api.spotify.com"
+	defaultSpotifyAccountsBaseURL = "https://accounts.spotify.com"
+)
+
+var (
+	spotifyIDPattern = regexp.MustCompile(`^[0-9A-Za-z]{22}$`)
+
+	// spotifyTrackURLPattern matches open.spotify.com track links, which
+	// may carry a locale segment such as /intl-de/.
+	spotifyTrackURLPattern = regexp.MustCompile(`^(?:https?://)?open\.spotify\.com/(?:intl-[a-z-]+/)?track/([0-9A-Za-z]{22})(?:[/?#].*)?$`)
+)
+
+// ParseSpotifyTrackID extracts the track ID from a spotify:track: URI, an
+// open.spotify.com link or a bare ID.
+func ParseSpotifyTrackID(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+
+	if id, found := strings.CutPrefix(s, "spotify:track:"); found {
+		return id, spotifyIDPattern.MatchString(id)
+	}
+	if match := spotifyTrackURLPattern.FindStringSubmatch(s); match != nil {
+		return match[1], true
+	}
+	return s, spotifyIDPattern.MatchString(s)
+}
+
+// SpotifyClient is a TrackProvider backed by the Spotify Web API, or any
+// server implementing its /v1/tracks endpoint. When a client ID is
+// configured, it authenticates with the client credentials flow.
+type SpotifyClient struct {
+	apiBaseURL      string
+	accountsBaseURL string
+	clientID        string
+	clientSecret    string
+	client          *http.Client
+	cache           *LRUCache
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+func NewSpotifyClient(config Config) *SpotifyClient {
+	apiBaseURL := config.SpotifyAPIBaseURL
+	if apiBaseURL == "" {
+		apiBaseURL = defaultSpotifyAPIBaseURL
+	}
+	accountsBaseURL := config.SpotifyAccountsBaseURL
+	if accountsBaseURL == "" {
+		accountsBaseURL = defaultSpotifyAccountsBaseURL
+	}
+
+	return &SpotifyClient{
+		apiBaseURL:      strings.TrimRight(apiBaseURL, "/"),
+		accountsBaseURL: strings.TrimRight(accountsBaseURL, "/"),
+		clientID:        config.SpotifyClientID,
+		clientSecret:    config.SpotifyClientSecret,
+		client:          &http.Client{Timeout: config.Timeout},
+		cache:           NewLRUCache(5000),
+	}
+}
+
+// LookupTrack returns the title, artists and duration of the track with
+// the given ID. Results are cached by ID.
+func (sc *SpotifyClient) LookupTrack(id string) (*Recording, error) {
+	return cachedRecording(sc.cache, "track:"+id, func() (*Recording, error) {
+		req, err := http.NewRequest("GET", sc.apiBaseURL+"/v1/tracks/"+url.PathEscape(id), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		if sc.clientID != "" {
+			token, err := sc.token()
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := sc.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query Spotify: %w", err)
+		}
+		defer resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			return nil, ErrRecordingNotFound
+		case resp.StatusCode != http.StatusOK:
+			return nil, fmt.Errorf("failed to query Spotify: status %d", resp.StatusCode)
+		}
+
+		var track struct {
+			ID         string `json:"id"`
+			Name       string `json:"name"`
+			DurationMs int    `json:"duration_ms"`
+			Artists    []struct {
+				Name string `json:"name"`
+			} `json:"artists"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&track); err != nil {
+			return nil, fmt.Errorf("failed to decode Spotify response: %w", err)
+		}
+
+		recording := &Recording{
+			ID:       track.ID,
+			Title:    track.Name,
+			Duration: (track.DurationMs + 500) / 1000,
+		}
+		for _, artist := range track.Artists {
+			recording.Artists = append(recording.Artists, artist.Name)
+		}
+		return recording, nil
+	})
+}
+
+// token returns a client credentials access token, requesting a new one
+// shortly before the current one expires.
+func (sc *SpotifyClient) token() (string, error) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.accessToken != "" && time.Now().Before(sc.expiresAt) {
+		return sc.accessToken, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequest("POST", sc.accountsBaseURL+"/api/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(sc.clientID, sc.clientSecret)
+
+	resp, err := sc.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request Spotify token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to request Spotify token: status %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode Spotify token: %w", err)
+	}
+
+	sc.accessToken = token.AccessToken
+	sc.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return sc.accessToken, nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseSpotifyTrackID(t *testing.T) {
+	tests := []struct {
+		input string
+		valid bool
+	}{
+		{"spotify:track:4uLU6hMCjMI75M1A2tKUQC", true},
+		{"https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC", true},
+		{"https://open.spotify.com/track/4uLU6hMCjMI75M1A2tKUQC?si=abc123", true},
+		{"open.spotify.com/intl-de/track/4uLU6hMCjMI75M1A2tKUQC", true},
+		{" 4uLU6hMCjMI75M1A2tKUQC ", true},
+		{"spotify:album:4uLU6hMCjMI75M1A2tKUQC", false},
+		{"https://open.spotify.com/album/4uLU6hMCjMI75M1A2tKUQC", false},
+		{"spotify:track:short", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		id, valid := ParseSpotifyTrackID(tt.input)
+		if valid != tt.valid {
+			t.Errorf("ParseSpotifyTrackID(%q) valid = %v, expected %v", tt.input, valid, tt.valid)
+		}
+		if tt.valid && id != "4uLU6hMCjMI75M1A2tKUQC" {
+			t.Errorf("ParseSpotifyTrackID(%q) = %q", tt.input, id)
+		}
+	}
+}
+
+func TestSpotifyClient_LookupTrack(t *testing.T) {
+	tokenRequests, trackRequests := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/token":
+			tokenRequests++
+			if id, secret, ok := r.BasicAuth(); !ok || id != "client" || secret != "secret" {
+				t.Errorf("Unexpected credentials %q/%q", id, secret)
+			}
+			if r.FormValue("grant_type") != "client_credentials" {
+				t.Errorf("Unexpected grant type %q", r.FormValue("grant_type"))
+			}
+			w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+		case "/v1/tracks/4uLU6hMCjMI75M1A2tKUQC":
+			trackRequests++
+			if r.Header.Get("Authorization") != "Bearer token" {
+				t.Errorf("Unexpected authorization %q", r.Header.Get("Authorization"))
+			}
+			w.Write([]byte(`{"id":"4uLU6hMCjMI75M1A2tKUQC","name":"Never Gonna Give You Up","duration_ms":213573,` +
+				`"artists":[{"name":"Rick Astley"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.SpotifyAPIBaseURL = server.URL
+	config.SpotifyAccountsBaseURL = server.URL
+	config.SpotifyClientID = "client"
+	config.SpotifyClientSecret = "secret"
+	sc := NewSpotifyClient(config)
+
+	for range 2 {
+		recording, err := sc.LookupTrack("4uLU6hMCjMI75M1A2tKUQC")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if recording.Title != "Never Gonna Give You Up" || recording.Duration != 214 || len(recording.Artists) != 1 {
+			t.Errorf("Unexpected recording %+v", recording)
+		}
+	}
+
+	if _, err := sc.LookupTrack("0000000000000000000000"); !errors.Is(err, ErrRecordingNotFound) {
+		t.Errorf("Expected ErrRecordingNotFound, got %v", err)
+	}
+
+	if tokenRequests != 1 {
+		t.Errorf("Expected the token to be reused, got %d token requests", tokenRequests)
+	}
+	if trackRequests != 1 {
+		t.Errorf("Expected the second lookup to be cached, got %d track requests", trackRequests)
+	}
+}
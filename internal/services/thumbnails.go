@@ -0,0 +1,237 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"slices"
+)
+
+// ErrThumbnailNotFound is returned when YouTube has no thumbnail for the
+// video, which usually means the video doesn't exist.
+var ErrThumbnailNotFound = errors.New("thumbnail not found")
+
+const (
+	// maxThumbnailBytes bounds how much of an upstream image is read.
+	maxThumbnailBytes = 5 << 20
+
+	thumbnailJPEGQuality = 85
+)
+
+// ThumbnailQualities lists YouTube's thumbnail variants from the smallest,
+// 120x90, to the largest, 1280x720. Every video has the first three; the
+// others only exist when the upload is large enough.
+var ThumbnailQualities = []string{"default", "mq", "hq", "sd", "maxres"}
+
+// thumbnailFiles maps each quality to YouTube's file name for it.
+var thumbnailFiles = map[string]string{
+	"default": "default",
+	"mq":      "mqdefault",
+	"hq":      "hqdefault",
+	"sd":      "sddefault",
+	"maxres":  "maxresdefault",
+}
+
+// ThumbnailOptions selects a thumbnail variant and how it is returned.
+type ThumbnailOptions struct {
+	// Quality is one of ThumbnailQualities. When the video doesn't have
+	// it, the next smaller variant is used.
+	Quality string
+
+	// WebP returns a WebP image: the variant YouTube serves when it has
+	// one, and otherwise, or when resizing, the JPEG converted to lossless
+	// WebP.
+	WebP bool
+
+	// Width and Height bound the size of the returned image, which keeps
+	// its aspect ratio. 0 leaves a dimension unbounded; both 0 return the
+	// image as YouTube serves it.
+	Width  int
+	Height int
+}
+
+func (opts ThumbnailOptions) resized() bool {
+	return opts.Width > 0 || opts.Height > 0
+}
+
+// GetThumbnail returns the encoded thumbnail image for videoID. Images are
+// cached on disk when the service has a thumbnail cache.
+func (ys *YouTubeService) GetThumbnail(videoID string, opts ThumbnailOptions) ([]byte, error) {
+	if !slices.Contains(ThumbnailQualities, opts.Quality) {
+		return nil, fmt.Errorf("unknown thumbnail quality %q", opts.Quality)
+	}
+
+	cacheKey := fmt.Sprintf("thumbnail:%s:%s:webp=%t:%dx%d", videoID, opts.Quality, opts.WebP, opts.Width, opts.Height)
+	if ys.thumbnails != nil {
+		if data, found := ys.thumbnails.Get(cacheKey); found {
+			return data, nil
+		}
+	}
+
+	var data []byte
+	var err error
+	if opts.resized() {
+		// Resize the cached JPEG original so every size doesn't refetch it.
+		original := ThumbnailOptions{Quality: opts.Quality}
+		if data, err = ys.GetThumbnail(videoID, original); err != nil {
+			return nil, err
+		}
+		data, err = convertJPEG(data, opts)
+	} else {
+		data, err = ys.fetchThumbnail(videoID, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if ys.thumbnails != nil {
+		ys.thumbnails.Put(cacheKey, data)
+	}
+	return data, nil
+}
+
+// fetchThumbnail downloads the requested variant, falling back to smaller
+// ones that exist. A WebP request takes the JPEG of the same quality, and
+// converts it, when YouTube has no WebP variant.
+func (ys *YouTubeService) fetchThumbnail(videoID string, opts ThumbnailOptions) ([]byte, error) {
+	for i := slices.Index(ThumbnailQualities, opts.Quality); i >= 0; i-- {
+		name := thumbnailFiles[ThumbnailQualities[i]]
+
+		if opts.WebP {
+			data, err := ys.fetchThumbnailFile(ys.config.ThumbnailBaseURL + "/vi_webp/" + videoID + "/" + name + ".webp")
+			if !errors.Is(err, ErrThumbnailNotFound) {
+				return data, err
+			}
+		}
+
+		data, err := ys.fetchThumbnailFile(ys.config.ThumbnailBaseURL + "/vi/" + videoID + "/" + name + ".jpg")
+		if errors.Is(err, ErrThumbnailNotFound) {
+			continue
+		}
+		if err != nil || !opts.WebP {
+			return data, err
+		}
+		return convertJPEG(data, opts)
+	}
+
+	return nil, ErrThumbnailNotFound
+}
+
+// fetchThumbnailFile downloads one thumbnail image, returning
+// ErrThumbnailNotFound when it doesn't exist.
+func (ys *YouTubeService) fetchThumbnailFile(thumbnailURL string) ([]byte, error) {
+	req, err := http.NewRequest("GET", thumbnailURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := ys.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch thumbnail: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrThumbnailNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch thumbnail: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxThumbnailBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read thumbnail: %w", err)
+	}
+	if len(data) > maxThumbnailBytes {
+		return nil, fmt.Errorf("thumbnail is larger than %d bytes", maxThumbnailBytes)
+	}
+	return data, nil
+}
+
+// convertJPEG scales a JPEG image to fit within opts.Width by opts.Height,
+// keeping its aspect ratio, and encodes it as WebP when opts.WebP is set.
+func convertJPEG(data []byte, opts ThumbnailOptions) ([]byte, error) {
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode thumbnail: %w", err)
+	}
+
+	if opts.resized() {
+		bounds := img.Bounds()
+		width, height := fitSize(bounds.Dx(), bounds.Dy(), opts.Width, opts.Height)
+		img = resizeImage(img, width, height)
+	}
+
+	if opts.WebP {
+		data, err := encodeWebP(img)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+		}
+		return data, nil
+	}
+
+	var b bytes.Buffer
+	if err := jpeg.Encode(&b, img, &jpeg.Options{Quality: thumbnailJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+// fitSize returns the largest size with the aspect ratio of srcWidth by
+// srcHeight that fits within maxWidth by maxHeight, where 0 means
+// unbounded.
+func fitSize(srcWidth, srcHeight, maxWidth, maxHeight int) (int, int) {
+	scale := 0.0
+	if maxWidth > 0 {
+		scale = float64(maxWidth) / float64(srcWidth)
+	}
+	if maxHeight > 0 {
+		if heightScale := float64(maxHeight) / float64(srcHeight); scale == 0 || heightScale < scale {
+			scale = heightScale
+		}
+	}
+
+	width := max(1, int(float64(srcWidth)*scale+0.5))
+	height := max(1, int(float64(srcHeight)*scale+0.5))
+	return width, height
+}
+
+// resizeImage scales src to width by height. Each destination pixel is the
+// average of the source pixels it covers, which keeps downscaled images
+// smooth; when enlarging, pixels are repeated.
+func resizeImage(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*srcHeight/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcHeight/height)
+
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*srcWidth/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcWidth/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8(r / n >> 8)
+			dst.Pix[i+1] = uint8(g / n >> 8)
+			dst.Pix[i+2] = uint8(b / n >> 8)
+			dst.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+func testJPEG(t *testing.T, width, height int) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+
+	var b bytes.Buffer
+	if err := jpeg.Encode(&b, img, nil); err != nil {
+		t.Fatalf("Failed to encode JPEG: %v", err)
+	}
+	return b.Bytes()
+}
+
+func TestYouTubeService_GetThumbnail(t *testing.T) {
+	hq := testJPEG(t, 480, 360)
+	var requested []string
+	ys := newStubService(t, func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		switch r.URL.Path {
+		case "/vi/dQw4w9WgXcQ/hqdefault.jpg":
+			w.Write(hq)
+		case "/vi_webp/dQw4w9WgXcQ/hqdefault.webp":
+			w.Write([]byte("RIFF webp"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	// The video has no maxres or sd thumbnail, so hq is used.
+	for range 2 {
+		data, err := ys.GetThumbnail("dQw4w9WgXcQ", ThumbnailOptions{Quality: "maxres"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !bytes.Equal(data, hq) {
+			t.Errorf("Expected the hq thumbnail, got %d bytes", len(data))
+		}
+	}
+
+	expected := []string{"/vi/dQw4w9WgXcQ/maxresdefault.jpg", "/vi/dQw4w9WgXcQ/sddefault.jpg", "/vi/dQw4w9WgXcQ/hqdefault.jpg"}
+	if len(requested) != len(expected) {
+		t.Fatalf("Expected requests %v, got %v", expected, requested)
+	}
+	for i := range expected {
+		if requested[i] != expected[i] {
+			t.Errorf("Expected request %d for %s, got %s", i, expected[i], requested[i])
+		}
+	}
+
+	data, err := ys.GetThumbnail("dQw4w9WgXcQ", ThumbnailOptions{Quality: "hq", WebP: true})
+	if err != nil || string(data) != "RIFF webp" {
+		t.Errorf("Expected the WebP thumbnail, got %q (%v)", data, err)
+	}
+}
+
+func TestYouTubeService_GetThumbnailResized(t *testing.T) {
+	requests := 0
+	ys := newStubService(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(testJPEG(t, 480, 360))
+	})
+
+	for _, size := range [][2]int{{240, 0}, {0, 90}} {
+		data, err := ys.GetThumbnail("dQw4w9WgXcQ", ThumbnailOptions{Quality: "hq", Width: size[0], Height: size[1]})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Failed to decode resized thumbnail: %v", err)
+		}
+		if config.Width*3 != config.Height*4 || (size[0] > 0 && config.Width != size[0]) || (size[1] > 0 && config.Height != size[1]) {
+			t.Errorf("Expected %v to keep the 4:3 aspect ratio, got %dx%d", size, config.Width, config.Height)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected the original to be fetched once, got %d requests", requests)
+	}
+}
+
+func TestYouTubeService_GetThumbnailConvertedToWebP(t *testing.T) {
+	var requested []string
+	ys := newStubService(t, func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.URL.Path != "/vi/dQw4w9WgXcQ/hqdefault.jpg" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(testJPEG(t, 480, 360))
+	})
+
+	// The video has no WebP variant, so the JPEG is converted.
+	for _, tt := range []struct{ maxWidth, width int }{{0, 480}, {240, 240}} {
+		data, err := ys.GetThumbnail("dQw4w9WgXcQ", ThumbnailOptions{Quality: "hq", WebP: true, Width: tt.maxWidth})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		config, err := webp.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Failed to decode WebP thumbnail: %v", err)
+		}
+		if config.Width != tt.width || config.Width*3 != config.Height*4 {
+			t.Errorf("Expected a 4:3 WebP thumbnail %d wide, got %dx%d", tt.width, config.Width, config.Height)
+		}
+	}
+
+	if len(requested) < 2 || requested[0] != "/vi_webp/dQw4w9WgXcQ/hqdefault.webp" || requested[1] != "/vi/dQw4w9WgXcQ/hqdefault.jpg" {
+		t.Errorf("Expected the WebP variant to be tried before the JPEG, got %v", requested)
+	}
+}
+
+func TestYouTubeService_GetThumbnailNotFound(t *testing.T) {
+	ys := newStubService(t, http.NotFound)
+
+	_, err := ys.GetThumbnail("xxxxxxxxxxx", ThumbnailOptions{Quality: "hq"})
+	if !errors.Is(err, ErrThumbnailNotFound) {
+		t.Errorf("Expected ErrThumbnailNotFound, got %v", err)
+	}
+}
+
+func TestFitSize(t *testing.T) {
+	tests := []struct {
+		maxWidth, maxHeight int
+		width, height       int
+	}{
+		{320, 0, 320, 180},
+		{0, 90, 160, 90},
+		{320, 90, 160, 90},
+		{1000, 1000, 1000, 563},
+	}
+
+	for _, tt := range tests {
+		width, height := fitSize(1280, 720, tt.maxWidth, tt.maxHeight)
+		if width != tt.width || height != tt.height {
+			t.Errorf("fitSize(1280, 720, %d, %d) = %dx%d, expected %dx%d",
+				tt.maxWidth, tt.maxHeight, width, height, tt.width, tt.height)
+		}
+	}
+}
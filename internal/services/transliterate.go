@@ -0,0 +1,147 @@
+package services
+
+import "strings"
+
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u", 'ђ': "dj", 'ј': "j",
+	'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz",
+}
+
+var greekToLatin = map[rune]string{
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o", 'ά': "a", 'έ': "e", 'ή': "i", 'ί': "i", 'ό': "o", 'ύ': "y", 'ώ': "o",
+}
+
+// hiraganaToRomaji uses Hepburn romanization. Katakana is mapped onto
+// hiragana before lookup.
+var hiraganaToRomaji = map[string]string{
+	"あ": "a", "い": "i", "う": "u", "え": "e", "お": "o",
+	"か": "ka", "き": "ki", "く": "ku", "け": "ke", "こ": "ko",
+	"さ": "sa", "し": "shi", "す": "su", "せ": "se", "そ": "so",
+	"た": "ta", "ち": "chi", "つ": "tsu", "て": "te", "と": "to",
+	"な": "na", "に": "ni", "ぬ": "nu", "ね": "ne", "の": "no",
+	"は": "ha", "ひ": "hi", "ふ": "fu", "へ": "he", "ほ": "ho",
+	"ま": "ma", "み": "mi", "む": "mu", "め": "me", "も": "mo",
+	"や": "ya", "ゆ": "yu", "よ": "yo",
+	"ら": "ra", "り": "ri", "る": "ru", "れ": "re", "ろ": "ro",
+	"わ": "wa", "ゐ": "i", "ゑ": "e", "を": "o", "ん": "n",
+	"が": "ga", "ぎ": "gi", "ぐ": "gu", "げ": "ge", "ご": "go",
+	"ざ": "za", "じ": "ji", "ず": "zu", "ぜ": "ze", "ぞ": "zo",
+	"だ": "da", "ぢ": "ji", "づ": "zu", "で": "de", "ど": "do",
+	"ば": "ba", "び": "bi", "ぶ": "bu", "べ": "be", "ぼ": "bo",
+	"ぱ": "pa", "ぴ": "pi", "ぷ": "pu", "ぺ": "pe", "ぽ": "po",
+	"ゔ": "vu", "ぁ": "a", "ぃ": "i", "ぅ": "u", "ぇ": "e", "ぉ": "o",
+	"ゃ": "ya", "ゅ": "yu", "ょ": "yo", "ゎ": "wa",
+	"きゃ": "kya", "きゅ": "kyu", "きょ": "kyo", "しゃ": "sha", "しゅ": "shu", "しょ": "sho",
+	"ちゃ": "cha", "ちゅ": "chu", "ちょ": "cho", "にゃ": "nya", "にゅ": "nyu", "にょ": "nyo",
+	"ひゃ": "hya", "ひゅ": "hyu", "ひょ": "hyo", "みゃ": "mya", "みゅ": "myu", "みょ": "myo",
+	"りゃ": "rya", "りゅ": "ryu", "りょ": "ryo", "ぎゃ": "gya", "ぎゅ": "gyu", "ぎょ": "gyo",
+	"じゃ": "ja", "じゅ": "ju", "じょ": "jo", "びゃ": "bya", "びゅ": "byu", "びょ": "byo",
+	"ぴゃ": "pya", "ぴゅ": "pyu", "ぴょ": "pyo",
+	"ふぁ": "fa", "ふぃ": "fi", "ふぇ": "fe", "ふぉ": "fo", "てぃ": "ti", "でぃ": "di",
+	"うぃ": "wi", "うぇ": "we", "うぉ": "wo", "しぇ": "she", "じぇ": "je", "ちぇ": "che",
+}
+
+// Revised Romanization of Korean for the initial, medial and final jamo of a
+// Hangul syllable.
+var (
+	hangulInitials = []string{"g", "kk", "n", "d", "tt", "r", "m", "b", "pp", "s", "ss", "", "j", "jj", "ch", "k", "t", "p", "h"}
+	hangulMedials  = []string{"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae", "oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui", "i"}
+	hangulFinals   = []string{"", "k", "k", "k", "n", "n", "n", "t", "l", "k", "m", "l", "l", "l", "p", "l", "m", "p", "p", "t", "t", "ng", "t", "t", "k", "t", "p", "t"}
+)
+
+// Transliterate romanizes Cyrillic, Greek, Japanese kana and Korean Hangul,
+// leaving other characters unchanged. Kanji are left as-is since reading
+// them requires a dictionary.
+func Transliterate(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		lower := []rune(strings.ToLower(string(r)))[0]
+
+		switch {
+		case cyrillicToLatin[lower] != "" || lower == 'ъ' || lower == 'ь':
+			b.WriteString(matchCase(cyrillicToLatin[lower], r != lower))
+		case greekToLatin[lower] != "":
+			b.WriteString(matchCase(greekToLatin[lower], r != lower))
+		case isKana(r):
+			consumed := writeKana(&b, runes[i:])
+			i += consumed - 1
+		case r >= 0xAC00 && r <= 0xD7A3:
+			syllable := int(r - 0xAC00)
+			b.WriteString(hangulInitials[syllable/588])
+			b.WriteString(hangulMedials[syllable%588/28])
+			b.WriteString(hangulFinals[syllable%28])
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+func isKana(r rune) bool {
+	return (r >= 0x3041 && r <= 0x3096) || (r >= 0x30A1 && r <= 0x30FA) || r == 'ー' || r == 'ッ' || r == 'っ'
+}
+
+func toHiragana(r rune) rune {
+	if r >= 0x30A1 && r <= 0x30F6 {
+		return r - 0x60
+	}
+	return r
+}
+
+// writeKana romanizes the kana at the start of runes and returns how many
+// runes it consumed.
+func writeKana(b *strings.Builder, runes []rune) int {
+	r := toHiragana(runes[0])
+
+	switch r {
+	case 'っ':
+		// A small tsu doubles the following consonant.
+		if len(runes) > 1 {
+			var next strings.Builder
+			writeKana(&next, runes[1:])
+			if romaji := next.String(); romaji != "" && !strings.ContainsRune("aeiou", rune(romaji[0])) {
+				b.WriteByte(romaji[0])
+			}
+		}
+		return 1
+	case 'ー':
+		// The long vowel mark repeats the previous vowel.
+		written := b.String()
+		if len(written) > 0 && strings.ContainsRune("aeiou", rune(written[len(written)-1])) {
+			b.WriteByte(written[len(written)-1])
+		}
+		return 1
+	}
+
+	if len(runes) > 1 {
+		if romaji, ok := hiraganaToRomaji[string([]rune{r, toHiragana(runes[1])})]; ok {
+			b.WriteString(romaji)
+			return 2
+		}
+	}
+
+	if romaji, ok := hiraganaToRomaji[string(r)]; ok {
+		b.WriteString(romaji)
+	} else {
+		b.WriteRune(runes[0])
+	}
+	return 1
+}
+
+func matchCase(s string, upper bool) string {
+	if upper && s != "" {
+		return strings.ToUpper(s[:1]) + s[1:]
+	}
+	return s
+}
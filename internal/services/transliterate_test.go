@@ -0,0 +1,31 @@
+package services
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Кино", "Kino"},
+		{"Группа крови", "Gruppa krovi"},
+		{"Щедрик", "Shchedrik"},
+		{"Ελλάδα", "Ellada"},
+		{"さくら", "sakura"},
+		{"ありがとう", "arigatou"},
+		{"きょう", "kyou"},
+		{"ずっと", "zutto"},
+		{"カタカナ", "katakana"},
+		{"ラーメン", "raamen"},
+		{"강남스타일", "gangnamseutail"},
+		{"아리랑", "arirang"},
+		{"Plain ASCII", "Plain ASCII"},
+		{"東京 さくら", "東京 sakura"},
+	}
+
+	for _, test := range tests {
+		if result := Transliterate(test.input); result != test.expected {
+			t.Errorf("Transliterate(%q) = %q; want %q", test.input, result, test.expected)
+		}
+	}
+}
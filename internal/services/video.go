@@ -0,0 +1,151 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrVideoNotFound is returned when YouTube has no video with the
+// requested ID.
+var ErrVideoNotFound = errors.New("video not found")
+
+var videoIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// IsVideoID reports whether id has the shape of a YouTube video ID.
+func IsVideoID(id string) bool {
+	return videoIDPattern.MatchString(id)
+}
+
+type Thumbnail struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// MusicAttribution is an entry in the "Music in this video" section of a
+// watch page's description.
+type MusicAttribution struct {
+	Song       string `json:"song,omitempty"`
+	Artist     string `json:"artist,omitempty"`
+	Album      string `json:"album,omitempty"`
+	LicensedTo string `json:"licensedTo,omitempty"`
+}
+
+// VideoDetails is the metadata of a single video as shown on its watch
+// page.
+type VideoDetails struct {
+	ID          string             `json:"id"`
+	Title       string             `json:"title"`
+	Channel     string             `json:"channel"`
+	ChannelID   string             `json:"channelId,omitempty"`
+	Duration    int                `json:"duration,omitempty"` // seconds
+	PublishDate string             `json:"publishDate,omitempty"`
+	ViewCount   int64              `json:"viewCount"`
+	Thumbnails  []Thumbnail        `json:"thumbnails,omitempty"`
+	Music       []MusicAttribution `json:"music,omitempty"`
+}
+
+// GetVideoDetails fetches the watch page for videoID and extracts its
+// metadata and music attribution. Results are cached.
+func (ys *YouTubeService) GetVideoDetails(videoID string) (*VideoDetails, error) {
+	if cached, found := ys.videos.Get(videoID); found {
+		var details VideoDetails
+		if err := json.Unmarshal([]byte(cached), &details); err == nil {
+			return &details, nil
+		}
+	}
+
+	html, err := ys.fetchWatchPage(videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, details, err := ys.cacheWatchPage(videoID, html)
+	if err != nil {
+		return nil, err
+	}
+	if details == nil {
+		return nil, ErrVideoNotFound
+	}
+	return details, nil
+}
+
+// newVideoDetails returns the metadata of the video on a watch page, or nil
+// when it names no video.
+func newVideoDetails(player *playerResponse, html string) *VideoDetails {
+	video := player.VideoDetails
+	if video.VideoID == "" {
+		return nil
+	}
+
+	details := &VideoDetails{
+		ID:          video.VideoID,
+		Title:       video.Title,
+		Channel:     video.Author,
+		ChannelID:   video.ChannelID,
+		PublishDate: player.Microformat.PlayerMicroformatRenderer.PublishDate,
+		Thumbnails:  video.Thumbnail.Thumbnails,
+		Music:       extractMusicAttributions(html),
+	}
+	details.Duration, _ = strconv.Atoi(video.LengthSeconds)
+	details.ViewCount, _ = strconv.ParseInt(video.ViewCount, 10, 64)
+	return details
+}
+
+// extractMusicAttributions reads the "Music in this video" section from the
+// page's ytInitialData. Each song is a carouselLockupRenderer whose rows are
+// infoRowRenderers titled SONG, ARTIST, ALBUM and LICENSES.
+func extractMusicAttributions(html string) []MusicAttribution {
+	data, ok := extractJSONAfter(html, initialDataMarkers...)
+	if !ok {
+		return nil
+	}
+
+	var attributions []MusicAttribution
+	walkJSON(data, func(key string, value any) bool {
+		if key != "carouselLockupRenderer" {
+			return true
+		}
+
+		lockup, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		var attribution MusicAttribution
+		rows, _ := lockup["infoRows"].([]any)
+		for _, row := range rows {
+			object, _ := row.(map[string]any)
+			info, ok := object["infoRowRenderer"].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			text := textAt(info, "defaultMetadata")
+			if text == "" {
+				text = textAt(info, "expandedMetadata")
+			}
+
+			switch strings.ToUpper(textAt(info, "title")) {
+			case "SONG":
+				attribution.Song = text
+			case "ARTIST":
+				attribution.Artist = text
+			case "ALBUM":
+				attribution.Album = text
+			case "LICENSES", "LICENSED TO YOUTUBE BY":
+				attribution.LicensedTo = text
+			}
+		}
+
+		if attribution != (MusicAttribution{}) {
+			attributions = append(attributions, attribution)
+		}
+		return false
+	})
+
+	return attributions
+}
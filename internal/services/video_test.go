@@ -0,0 +1,116 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestIsVideoID(t *testing.T) {
+	for _, id := range []string{"dQw4w9WgXcQ", "a-b_c-d_e-f"} {
+		if !IsVideoID(id) {
+			t.Errorf("Expected %q to be a video ID", id)
+		}
+	}
+	for _, id := range []string{"", "dQw4w9WgXc", "dQw4w9WgXcQQ", "dQw4w9WgXc!"} {
+		if IsVideoID(id) {
+			t.Errorf("Expected %q to be rejected", id)
+		}
+	}
+}
+
+func videoPlayer() map[string]any {
+	return map[string]any{
+		"playabilityStatus": map[string]any{"status": "OK"},
+		"videoDetails": map[string]any{
+			"videoId":       "dQw4w9WgXcQ",
+			"title":         "Rick Astley - Never Gonna Give You Up (Official Music Video)",
+			"author":        "Rick Astley",
+			"channelId":     "UCuAXFkgsw1L7xaCfnd5JJOw",
+			"lengthSeconds": "213",
+			"viewCount":     "1600000000",
+			"thumbnail": map[string]any{"thumbnails": []any{
+				map[string]any{"url": "https://i.ytimg.com/vi/dQw4w9WgXcQ/default.jpg", "width": 120, "height": 90},
+			}},
+		},
+		"microformat": map[string]any{
+			"playerMicroformatRenderer": map[string]any{"publishDate": "2009-10-24"},
+		},
+	}
+}
+
+func infoRow(title, value string) map[string]any {
+	return map[string]any{"infoRowRenderer": map[string]any{
+		"title":           map[string]any{"simpleText": title},
+		"defaultMetadata": map[string]any{"runs": []any{map[string]any{"text": value}}},
+	}}
+}
+
+// musicSection is the ytInitialData of a watch page listing one song under
+// "Music in this video".
+func musicSection() string {
+	data := map[string]any{"engagementPanels": []any{map[string]any{
+		"videoDescriptionMusicSectionRenderer": map[string]any{
+			"carouselLockups": []any{map[string]any{"carouselLockupRenderer": map[string]any{
+				"infoRows": []any{
+					infoRow("SONG", "Never Gonna Give You Up"),
+					infoRow("ARTIST", "Rick Astley"),
+					infoRow("ALBUM", "Whenever You Need Somebody"),
+					infoRow("LICENSES", "RCA Records Label"),
+				},
+			}}},
+		},
+	}}}
+	encoded, _ := json.Marshal(data)
+	return `<script>var ytInitialData = ` + string(encoded) + `;</script>`
+}
+
+func TestYouTubeService_GetVideoDetails(t *testing.T) {
+	requests := 0
+	ys := newStubService(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(watchPage(videoPlayer()) + musicSection()))
+	})
+
+	for range 2 {
+		details, err := ys.GetVideoDetails("dQw4w9WgXcQ")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if details.Title != "Rick Astley - Never Gonna Give You Up (Official Music Video)" || details.Channel != "Rick Astley" {
+			t.Errorf("Unexpected title/channel %q/%q", details.Title, details.Channel)
+		}
+		if details.Duration != 213 || details.ViewCount != 1600000000 || details.PublishDate != "2009-10-24" {
+			t.Errorf("Unexpected details %+v", details)
+		}
+		if len(details.Thumbnails) != 1 || details.Thumbnails[0].Width != 120 {
+			t.Errorf("Unexpected thumbnails %+v", details.Thumbnails)
+		}
+
+		expected := MusicAttribution{
+			Song:       "Never Gonna Give You Up",
+			Artist:     "Rick Astley",
+			Album:      "Whenever You Need Somebody",
+			LicensedTo: "RCA Records Label",
+		}
+		if len(details.Music) != 1 || details.Music[0] != expected {
+			t.Errorf("Unexpected music attribution %+v", details.Music)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected the second lookup to be cached, got %d requests", requests)
+	}
+}
+
+func TestYouTubeService_GetVideoDetailsNotFound(t *testing.T) {
+	ys := newStubService(t, stubYouTube("", map[string]map[string]any{
+		"xxxxxxxxxxx": {"playabilityStatus": map[string]any{"status": "ERROR", "reason": "Video unavailable"}},
+	}))
+
+	if _, err := ys.GetVideoDetails("xxxxxxxxxxx"); !errors.Is(err, ErrVideoNotFound) {
+		t.Errorf("Expected ErrVideoNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,429 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"math/bits"
+	"slices"
+)
+
+// WebP images are encoded losslessly, in the VP8L format. Only the subtract
+// green and predictor transforms are used, without LZ77 backward references
+// or a color cache, which keeps the encoder small at the cost of larger
+// files than a full encoder would write.
+const (
+	// webpTileBits is the log2 side of the square tiles that each choose
+	// their own predictor.
+	webpTileBits = 5
+
+	webpMaxDimension = 1 << 14
+
+	// Prefix codes are at most 15 bits long, and the code that encodes
+	// their code lengths at most 7.
+	webpMaxCodeLength           = 15
+	webpMaxCodeLengthCodeLength = 7
+
+	// The green alphabet also holds the 24 LZ77 length prefixes, and the
+	// distance alphabet has 40 symbols, though neither is used here.
+	webpGreenAlphabetSize    = 256 + 24
+	webpDistanceAlphabetSize = 40
+)
+
+// webpCodeLengthCodeOrder is the order the code length code's lengths are
+// written in.
+var webpCodeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// webpPredictors are the predictor modes each tile picks from: left, top,
+// top-right, top-left and the average of left and top.
+var webpPredictors = []uint8{1, 2, 3, 4, 7}
+
+// encodeWebP encodes img as a lossless WebP image.
+func encodeWebP(img image.Image) ([]byte, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 || width > webpMaxDimension || height > webpMaxDimension {
+		return nil, fmt.Errorf("can't encode a %dx%d image as WebP", width, height)
+	}
+
+	nrgba := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+	pix := nrgba.Pix
+
+	hasAlpha := false
+	for i := 3; i < len(pix); i += 4 {
+		if pix[i] != 0xff {
+			hasAlpha = true
+			break
+		}
+	}
+
+	// Transforms are applied in the order they are written; decoders undo
+	// them in reverse.
+	for i := 0; i < len(pix); i += 4 {
+		pix[i+0] -= pix[i+1]
+		pix[i+2] -= pix[i+1]
+	}
+	modes, residuals := predictWebP(pix, width, height)
+
+	var w webpBitWriter
+	w.writeBits(0x2f, 8) // VP8L signature
+	w.writeBits(uint32(width-1), 14)
+	w.writeBits(uint32(height-1), 14)
+	if hasAlpha {
+		w.writeBits(1, 1)
+	} else {
+		w.writeBits(0, 1)
+	}
+	w.writeBits(0, 3) // version
+
+	w.writeBits(1, 1)
+	w.writeBits(2, 2) // subtract green
+	w.writeBits(1, 1)
+	w.writeBits(0, 2) // predictor
+	w.writeBits(webpTileBits-2, 3)
+	w.writeImage(modes, false)
+	w.writeBits(0, 1) // no more transforms
+
+	w.writeImage(residuals, true)
+	data := w.bytes()
+
+	var b bytes.Buffer
+	padding := len(data) % 2
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(4+8+len(data)+padding))
+	b.WriteString("WEBPVP8L")
+	binary.Write(&b, binary.LittleEndian, uint32(len(data)))
+	b.Write(data)
+	if padding > 0 {
+		b.WriteByte(0)
+	}
+	return b.Bytes(), nil
+}
+
+// predictWebP picks the predictor with the smallest residuals for each tile
+// of pix, an RGBA image, and returns the predictor image and the residuals.
+// The mode of each tile is in the green channel of the predictor image.
+func predictWebP(pix []uint8, width, height int) ([]uint8, []uint8) {
+	tileSize := 1 << webpTileBits
+	tilesX := (width + tileSize - 1) / tileSize
+	tilesY := (height + tileSize - 1) / tileSize
+	modes := make([]uint8, 4*tilesX*tilesY)
+	residuals := make([]uint8, len(pix))
+
+	for ty := 0; ty < tilesY; ty++ {
+		for tx := 0; tx < tilesX; tx++ {
+			x0, y0 := tx*tileSize, ty*tileSize
+			x1, y1 := min(x0+tileSize, width), min(y0+tileSize, height)
+
+			best, bestCost := webpPredictors[0], -1
+			for _, mode := range webpPredictors {
+				cost := 0
+				for y := y0; y < y1; y++ {
+					for x := x0; x < x1; x++ {
+						i := 4 * (y*width + x)
+						for c := 0; c < 4; c++ {
+							residual := int8(pix[i+c] - webpPrediction(pix, width, x, y, c, mode))
+							cost += max(int(residual), -int(residual))
+						}
+					}
+				}
+				if bestCost < 0 || cost < bestCost {
+					best, bestCost = mode, cost
+				}
+			}
+
+			tile := 4 * (ty*tilesX + tx)
+			modes[tile+1] = best
+			modes[tile+3] = 0xff
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					i := 4 * (y*width + x)
+					for c := 0; c < 4; c++ {
+						residuals[i+c] = pix[i+c] - webpPrediction(pix, width, x, y, c, best)
+					}
+				}
+			}
+		}
+	}
+
+	return modes, residuals
+}
+
+// webpPrediction predicts channel c of the pixel at x, y from its
+// neighbours. The first pixel is predicted as opaque black, the rest of the
+// top row from the left and the left column from the top, whatever the mode.
+func webpPrediction(pix []uint8, width, x, y, c int, mode uint8) uint8 {
+	i := 4*(y*width+x) + c
+	switch {
+	case x == 0 && y == 0:
+		if c == 3 {
+			return 0xff
+		}
+		return 0
+	case y == 0:
+		return pix[i-4]
+	case x == 0:
+		return pix[i-4*width]
+	}
+
+	left, top := pix[i-4], pix[i-4*width]
+	switch mode {
+	case 1:
+		return left
+	case 2:
+		return top
+	case 3:
+		// In the rightmost column this is the first pixel of the current
+		// row, as the format specifies.
+		return pix[i-4*width+4]
+	case 4:
+		return pix[i-4*width-4]
+	default:
+		return uint8((uint16(left) + uint16(top)) / 2)
+	}
+}
+
+// webpBitWriter packs values least significant bit first, as VP8L expects.
+type webpBitWriter struct {
+	buf   []byte
+	acc   uint64
+	nBits uint
+}
+
+func (w *webpBitWriter) writeBits(value uint32, n uint) {
+	w.acc |= uint64(value) << w.nBits
+	w.nBits += n
+	for w.nBits >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.nBits -= 8
+	}
+}
+
+// bytes flushes any partial byte and returns everything written.
+func (w *webpBitWriter) bytes() []byte {
+	if w.nBits > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.nBits = 0, 0
+	}
+	return w.buf
+}
+
+// writeImage writes pix, an RGBA image, with one set of prefix codes.
+// spatial is set for the main image, whose header also says whether it has
+// meta prefix codes.
+func (w *webpBitWriter) writeImage(pix []uint8, spatial bool) {
+	w.writeBits(0, 1) // no color cache
+	if spatial {
+		w.writeBits(0, 1) // no meta prefix codes
+	}
+
+	// Pixels are coded green, red, blue, alpha, followed by the unused
+	// distance code.
+	histograms := [5][]int{
+		make([]int, webpGreenAlphabetSize),
+		make([]int, 256),
+		make([]int, 256),
+		make([]int, 256),
+		make([]int, webpDistanceAlphabetSize),
+	}
+	for i := 0; i < len(pix); i += 4 {
+		histograms[0][pix[i+1]]++
+		histograms[1][pix[i+0]]++
+		histograms[2][pix[i+2]]++
+		histograms[3][pix[i+3]]++
+	}
+
+	var codes [5]webpPrefixCode
+	for i, histogram := range histograms {
+		codes[i] = w.writePrefixCode(histogram)
+	}
+
+	for i := 0; i < len(pix); i += 4 {
+		codes[0].write(w, int(pix[i+1]))
+		codes[1].write(w, int(pix[i+0]))
+		codes[2].write(w, int(pix[i+2]))
+		codes[3].write(w, int(pix[i+3]))
+	}
+}
+
+// writePrefixCode writes a prefix code for the symbols counted in histogram
+// and returns it.
+func (w *webpBitWriter) writePrefixCode(histogram []int) webpPrefixCode {
+	var used []int
+	for symbol, count := range histogram {
+		if count > 0 {
+			used = append(used, symbol)
+		}
+	}
+
+	// A single symbol, or none, takes no bits to write.
+	if len(used) <= 1 && (len(used) == 0 || used[0] < 256) {
+		symbol := 0
+		if len(used) == 1 {
+			symbol = used[0]
+		}
+		w.writeBits(1, 1) // simple code
+		w.writeBits(0, 1) // of one symbol
+		if symbol < 2 {
+			w.writeBits(0, 1)
+			w.writeBits(uint32(symbol), 1)
+		} else {
+			w.writeBits(1, 1)
+			w.writeBits(uint32(symbol), 8)
+		}
+		return newWebPPrefixCode(make([]uint8, len(histogram)))
+	}
+
+	lengths := huffmanCodeLengths(histogram, webpMaxCodeLength)
+
+	// The code lengths are themselves prefix coded, using the literal
+	// lengths 0 to 15 and none of the repeat codes.
+	codeLengthHistogram := make([]int, len(webpCodeLengthCodeOrder))
+	for _, length := range lengths {
+		codeLengthHistogram[length]++
+	}
+	codeLengthLengths := huffmanCodeLengths(codeLengthHistogram, webpMaxCodeLengthCodeLength)
+
+	n := len(webpCodeLengthCodeOrder)
+	for n > 4 && codeLengthLengths[webpCodeLengthCodeOrder[n-1]] == 0 {
+		n--
+	}
+	w.writeBits(0, 1) // normal code
+	w.writeBits(uint32(n-4), 4)
+	for _, symbol := range webpCodeLengthCodeOrder[:n] {
+		w.writeBits(uint32(codeLengthLengths[symbol]), 3)
+	}
+	w.writeBits(0, 1) // a length for every symbol follows
+
+	codeLengthCode := newWebPPrefixCode(codeLengthLengths)
+	for _, length := range lengths {
+		codeLengthCode.write(w, int(length))
+	}
+	return newWebPPrefixCode(lengths)
+}
+
+// webpPrefixCode is a canonical prefix code, with each code bit-reversed so
+// it can be written least significant bit first.
+type webpPrefixCode struct {
+	codes   []uint16
+	lengths []uint8
+}
+
+// newWebPPrefixCode assigns canonical codes to the symbols with non-zero
+// lengths. A code with a single symbol is written with no bits at all.
+func newWebPPrefixCode(lengths []uint8) webpPrefixCode {
+	code := webpPrefixCode{codes: make([]uint16, len(lengths)), lengths: slices.Clone(lengths)}
+
+	var counts [webpMaxCodeLength + 1]int
+	used := 0
+	for _, length := range lengths {
+		if length > 0 {
+			counts[length]++
+			used++
+		}
+	}
+	if used <= 1 {
+		clear(code.lengths)
+		return code
+	}
+
+	var next [webpMaxCodeLength + 1]int
+	value := 0
+	for length := 1; length <= webpMaxCodeLength; length++ {
+		value = (value + counts[length-1]) << 1
+		next[length] = value
+	}
+
+	for symbol, length := range lengths {
+		if length > 0 {
+			code.codes[symbol] = bits.Reverse16(uint16(next[length])) >> (16 - length)
+			next[length]++
+		}
+	}
+	return code
+}
+
+func (code webpPrefixCode) write(w *webpBitWriter, symbol int) {
+	w.writeBits(uint32(code.codes[symbol]), uint(code.lengths[symbol]))
+}
+
+// huffmanCodeLengths returns the Huffman code lengths for histogram, none
+// longer than maxLength. When the optimal code is too deep, the counts are
+// flattened until it fits.
+func huffmanCodeLengths(histogram []int, maxLength int) []uint8 {
+	counts := slices.Clone(histogram)
+	for {
+		lengths, longest := buildHuffmanCodeLengths(counts)
+		if longest <= maxLength {
+			return lengths
+		}
+		for i, count := range counts {
+			if count > 1 {
+				counts[i] = (count + 1) / 2
+			}
+		}
+	}
+}
+
+// buildHuffmanCodeLengths returns the optimal code lengths for counts and
+// the longest of them. A lone symbol gets a length of 1.
+func buildHuffmanCodeLengths(counts []int) ([]uint8, int) {
+	lengths := make([]uint8, len(counts))
+
+	var symbols []int
+	for symbol, count := range counts {
+		if count > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+	switch len(symbols) {
+	case 0:
+		return lengths, 0
+	case 1:
+		lengths[symbols[0]] = 1
+		return lengths, 1
+	}
+	slices.SortStableFunc(symbols, func(a, b int) int { return counts[a] - counts[b] })
+
+	// The leaves come first, in order of weight, followed by the internal
+	// nodes, which are created in order of weight too, so the two lightest
+	// nodes are always at the front of one of the two runs.
+	n := len(symbols)
+	weights := make([]int, n, 2*n-1)
+	parents := make([]int, 2*n-1)
+	for i, symbol := range symbols {
+		weights[i] = counts[symbol]
+	}
+	leaf, internal := 0, n
+	lightest := func() int {
+		if leaf < n && (internal == len(weights) || weights[leaf] <= weights[internal]) {
+			leaf++
+			return leaf - 1
+		}
+		internal++
+		return internal - 1
+	}
+	for len(weights) < 2*n-1 {
+		a, b := lightest(), lightest()
+		parents[a], parents[b] = len(weights), len(weights)
+		weights = append(weights, weights[a]+weights[b])
+	}
+
+	// Parents always come after their children, so depths can be filled in
+	// from the root down.
+	depths := make([]int, 2*n-1)
+	for i := 2*n - 3; i >= 0; i-- {
+		depths[i] = depths[parents[i]] + 1
+	}
+
+	longest := 0
+	for i, symbol := range symbols {
+		lengths[symbol] = uint8(depths[i])
+		longest = max(longest, depths[i])
+	}
+	return lengths, longest
+}
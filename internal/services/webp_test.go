@@ -0,0 +1,81 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+func TestEncodeWebP(t *testing.T) {
+	gradient := image.NewNRGBA(image.Rect(0, 0, 70, 45))
+	noise := image.NewNRGBA(image.Rect(0, 0, 33, 17))
+	seed := uint32(1)
+	for y := 0; y < 45; y++ {
+		for x := 0; x < 70; x++ {
+			gradient.Set(x, y, color.NRGBA{uint8(3 * x), uint8(5 * y), uint8(x * y), 255})
+		}
+	}
+	for i := range noise.Pix {
+		seed = seed*1664525 + 1013904223
+		noise.Pix[i] = uint8(seed >> 24)
+	}
+
+	tests := []struct {
+		name string
+		img  *image.NRGBA
+	}{
+		{"gradient", gradient},
+		{"noise with alpha", noise},
+		{"single pixel", image.NewNRGBA(image.Rect(0, 0, 1, 1))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := encodeWebP(tt.img)
+			if err != nil {
+				t.Fatalf("Failed to encode: %v", err)
+			}
+
+			decoded, err := webp.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Failed to decode: %v", err)
+			}
+			if decoded.Bounds() != tt.img.Bounds() {
+				t.Fatalf("Expected bounds %v, got %v", tt.img.Bounds(), decoded.Bounds())
+			}
+			for y := 0; y < tt.img.Bounds().Dy(); y++ {
+				for x := 0; x < tt.img.Bounds().Dx(); x++ {
+					want := tt.img.NRGBAAt(x, y)
+					if got := color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA); got != want {
+						t.Fatalf("Expected %v at %d,%d, got %v", want, x, y, got)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestHuffmanCodeLengths_Limited(t *testing.T) {
+	// Fibonacci counts make the optimal code as deep as it can be.
+	histogram := make([]int, 30)
+	histogram[0], histogram[1] = 1, 1
+	for i := 2; i < len(histogram); i++ {
+		histogram[i] = histogram[i-1] + histogram[i-2]
+	}
+
+	lengths := huffmanCodeLengths(histogram, webpMaxCodeLength)
+
+	kraft := 0.0
+	for symbol, length := range lengths {
+		if length == 0 || length > webpMaxCodeLength {
+			t.Fatalf("Expected symbol %d to have a length between 1 and %d, got %d", symbol, webpMaxCodeLength, length)
+		}
+		kraft += 1 / float64(uint(1)<<length)
+	}
+	if kraft != 1 {
+		t.Errorf("Expected a complete code, got a Kraft sum of %v", kraft)
+	}
+}
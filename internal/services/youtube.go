@@ -1,6 +1,7 @@
 package services
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -8,41 +9,179 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+	"sync/atomic"
+	"time"
 )
 
 type YouTubeService struct {
-	client *http.Client
-	cache  *LRUCache
+	client  *http.Client
+	cache   *LRUCache
+	players *LRUCache
+	videos  *LRUCache
+	config  Config
+	proxies *ProxyPool
+
+	// thumbnails is nil when thumbnail caching is disabled.
+	thumbnails *DiskCache
+
+	userAgentIndex atomic.Uint64
 }
 
 func NewYouTubeService() *YouTubeService {
-	return &YouTubeService{
-		client: &http.Client{},
-		cache:  NewLRUCache(5000), // Cache up to 5000 search results
+	ys, err := NewYouTubeServiceWithConfig(DefaultConfig())
+	if err != nil {
+		panic(err)
+	}
+	return ys
+}
+
+func NewYouTubeServiceWithConfig(config Config) (*YouTubeService, error) {
+	if config.BaseURL == "" {
+		config.BaseURL = defaultBaseURL
+	}
+	if len(config.UserAgents) == 0 {
+		config.UserAgents = []string{defaultUserAgent}
+	}
+	if config.ProxyStrategy == "" {
+		config.ProxyStrategy = ProxyRoundRobin
+	}
+	if config.DurationTolerance == nil {
+		tolerance := defaultDurationTolerance
+		config.DurationTolerance = &tolerance
+	}
+	if config.ThumbnailBaseURL == "" {
+		config.ThumbnailBaseURL = defaultThumbnailBaseURL
+	}
+	if config.MusicBaseURL == "" {
+		config.MusicBaseURL = defaultMusicBaseURL
+	}
+	if config.SearchSource == "" {
+		config.SearchSource = SourceYouTube
+	}
+
+	ys := &YouTubeService{
+		client:  &http.Client{Timeout: config.Timeout},
+		cache:   NewLRUCache(5000), // Cache up to 5000 search results
+		players: NewLRUCache(5000),
+		videos:  NewLRUCache(5000),
+		config:  config,
+	}
+
+	if config.ThumbnailCacheSize > 0 && config.ThumbnailCacheDir != "" {
+		ys.thumbnails = NewDiskCache(config.ThumbnailCacheDir, config.ThumbnailCacheSize)
 	}
+
+	if len(config.Proxies) > 0 {
+		proxies, err := NewProxyPool(config.Proxies, config.ProxyStrategy, config.MaxProxyFailures, config.ProxyRetryAfter, config.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		ys.proxies = proxies
+	}
+
+	return ys, nil
+}
+
+// ProxyStats reports the state of the configured proxies, or nil when
+// requests are made directly.
+func (ys *YouTubeService) ProxyStats() []ProxyStats {
+	if ys.proxies == nil {
+		return nil
+	}
+	return ys.proxies.Stats()
+}
+
+// SearchOptions narrows a search to a market. Empty fields fall back to the
+// service configuration.
+type SearchOptions struct {
+	Region   string
+	Language string
+
+	// CheckAvailability reports the chosen video's availability, and
+	// RequirePlayable additionally skips candidates that can't be played
+	// in Region.
+	CheckAvailability bool
+	RequirePlayable   bool
+
+	// RequireEmbeddable skips candidates with embedding disabled, and
+	// ExcludeAgeRestricted skips ones logged-out viewers can't watch.
+	RequireEmbeddable    bool
+	ExcludeAgeRestricted bool
+
+	// Duration is the expected track length. Candidates further than
+	// DurationTolerance from it rank lower, or are skipped entirely when
+	// StrictDuration is set. A nil tolerance uses the configured default;
+	// 0 only accepts exact lengths.
+	Duration          time.Duration
+	DurationTolerance *time.Duration
+	StrictDuration    bool
+
+	// MinConfidence is the confidence, between 0 and 1, a candidate needs
+	// to be returned. nil uses the configured default; 0 disables the check.
+	MinConfidence *float64
+
+	// Album is added to the search query to disambiguate tracks that share
+	// a title, and ExcludeIDs skips videos already chosen for other tracks.
+	Album      string
+	ExcludeIDs []string
+
+	// Source is SourceYouTube or SourceMusic. Music searches also resolve
+	// the song's audio track.
+	Source string
 }
 
 func (ys *YouTubeService) SearchVideos(title string, artists []string) ([]string, error) {
+	return ys.SearchVideosWithOptions(title, artists, SearchOptions{})
+}
+
+func (ys *YouTubeService) SearchVideosWithOptions(title string, artists []string, opts SearchOptions) ([]string, error) {
+	results, _, err := ys.search(title, artists, ys.withDefaults(opts))
+	if err != nil {
+		return nil, err
+	}
+	
+	videoIDs := make([]string, 0, len(results))
+	for _, result := range results {
+		videoIDs = append(videoIDs, result.ID)
+	}
+	return videoIDs, nil
+}
+
+// searchResults returns the cached or freshly scraped search results for the
+// song. opts must already have defaults applied.
+func (ys *YouTubeService) searchResults(title string, artists []string, opts SearchOptions) ([]SearchResult, error) {
 	query := ys.buildSearchQuery(title, artists)
-	cacheKey := ys.buildCacheKey(title, artists)
+	if opts.Album != "" {
+		query += " " + opts.Album
+	}
+	cacheKey := ys.buildCacheKey(title, artists, opts)
 	
 	// Check cache first
-	if videoID, found := ys.cache.Get(cacheKey); found {
-		log.Printf("Cache HIT for key: %s", cacheKey)
-		return []string{videoID}, nil
+	if cached, found := ys.cache.Get(cacheKey); found {
+		var results []SearchResult
+		if err := json.Unmarshal([]byte(cached), &results); err == nil {
+			log.Printf("Cache HIT for key: %s", cacheKey)
+			return results, nil
+		}
 	}
 	log.Printf("Cache MISS for key: %s", cacheKey)
 	
-	searchURL := fmt.Sprintf("https://www.youtube.com/results?search_query=%s", url.QueryEscape(query))
+	params := url.Values{}
+	params.Set("search_query", query)
+	if opts.Language != "" {
+		params.Set("hl", opts.Language)
+	}
+	if opts.Region != "" {
+		params.Set("gl", opts.Region)
+	}
+	searchURL := ys.config.BaseURL + "/results?" + params.Encode()
 	
 	req, err := http.NewRequest("GET", searchURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 	
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-	
-	resp, err := ys.client.Do(req)
+	resp, err := ys.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch YouTube search results: %w", err)
 	}
@@ -57,30 +196,72 @@ func (ys *YouTubeService) SearchVideos(title string, artists []string) ([]string
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 	
-	videoIDs, err := ys.extractVideoIDs(string(html))
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract video IDs: %w", err)
+	results := extractSearchResults(string(html))
+	if len(results) == 0 {
+		// Fall back to bare IDs when the page has no parseable results data
+		videoIDs, err := ys.extractVideoIDs(string(html))
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract video IDs: %w", err)
+		}
+		for _, videoID := range videoIDs {
+			results = append(results, SearchResult{ID: videoID})
+		}
 	}
 	
-	// Cache the first video ID if found
-	if len(videoIDs) > 0 {
-		log.Printf("Caching video ID %s for key: %s", videoIDs[0], cacheKey)
-		ys.cache.Put(cacheKey, videoIDs[0])
+	// Cache all candidates so filtering can fall back to later ones
+	if encoded, err := json.Marshal(results); err == nil {
+		log.Printf("Caching %d search results for key: %s", len(results), cacheKey)
+		ys.cache.Put(cacheKey, string(encoded))
 	}
 	
-	return videoIDs, nil
+	return results, nil
 }
 
 func (ys *YouTubeService) buildSearchQuery(title string, artists []string) string {
-	parts := []string{title}
-	parts = append(parts, artists...)
+	credits := ParseCredits(title, artists)
+	parts := []string{credits.Title}
+	parts = append(parts, credits.Artists()...)
 	return strings.Join(parts, " ")
 }
 
-func (ys *YouTubeService) buildCacheKey(title string, artists []string) string {
-	parts := []string{title}
-	parts = append(parts, artists...)
-	return strings.Join(parts, " ")
+func (ys *YouTubeService) buildCacheKey(title string, artists []string, opts SearchOptions) string {
+	credits := ParseCredits(title, artists)
+	parts := []string{NormalizeTitle(credits.Title)}
+	// Featured artists are left out, so that "Song (feat. Y)" shares the
+	// results of "Song" by the same primary artists.
+	parts = append(parts, NormalizeArtists(credits.Primary)...)
+	key := strings.Join(parts, " ")
+	
+	if opts.Region != "" {
+		key += "|gl=" + opts.Region
+	}
+	if opts.Language != "" {
+		key += "|hl=" + opts.Language
+	}
+	if opts.Album != "" {
+		key += "|album=" + NormalizeTitle(opts.Album)
+	}
+	return key
+}
+
+func (ys *YouTubeService) withDefaults(opts SearchOptions) SearchOptions {
+	if opts.Region == "" {
+		opts.Region = ys.config.Region
+	}
+	if opts.Language == "" {
+		opts.Language = ys.config.Language
+	}
+	if opts.DurationTolerance == nil {
+		opts.DurationTolerance = ys.config.DurationTolerance
+	}
+	if opts.MinConfidence == nil {
+		minConfidence := ys.config.MinConfidence
+		opts.MinConfidence = &minConfidence
+	}
+	if opts.Source == "" {
+		opts.Source = ys.config.SearchSource
+	}
+	return opts
 }
 
 func (ys *YouTubeService) extractVideoIDs(html string) ([]string, error) {
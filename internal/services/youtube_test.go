@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 )
@@ -22,6 +23,8 @@ func TestYouTubeService_BuildSearchQuery(t *testing.T) {
 		{"Shape of You", []string{"Ed", "Sheeran"}, "Shape of You Ed Sheeran"},
 		{"Title Only", []string{}, "Title Only"},
 		{"Multi Artist", []string{"Artist1", "Artist2", "Artist3"}, "Multi Artist Artist1 Artist2 Artist3"},
+		{"Stay (with Justin Bieber)", []string{"The Kid LAROI & Justin Bieber"}, "Stay The Kid LAROI Justin Bieber"},
+		{"Song", []string{"A feat. B"}, "Song A B"},
 	}
 	
 	for _, test := range tests {
@@ -38,17 +41,27 @@ func TestYouTubeService_BuildCacheKey(t *testing.T) {
 	tests := []struct {
 		title    string
 		artists  []string
+		opts     SearchOptions
 		expected string
 	}{
-		{"Euphoria", []string{"Loreen"}, "Euphoria Loreen"},
-		{"Hello", []string{"Adele"}, "Hello Adele"},
-		{"Title Only", []string{}, "Title Only"},
+		{"Euphoria", []string{"Loreen"}, SearchOptions{}, "euphoria loreen"},
+		{"euphoria", []string{"LOREEN"}, SearchOptions{}, "euphoria loreen"},
+		{"Hello", []string{"Adele"}, SearchOptions{}, "hello adele"},
+		{"Title Only", []string{}, SearchOptions{}, "title only"},
+		{"Song (feat. Y)", []string{"X", "Loreen"}, SearchOptions{}, "song loreen x"},
+		{"Song", []string{"Loreen feat. Y", "X"}, SearchOptions{}, "song loreen x"},
+		{"Song", []string{"Loreen", "X", "Y"}, SearchOptions{}, "song loreen x y"},
+		{"Song", []string{"Loreen", "X"}, SearchOptions{}, "song loreen x"},
+		{"Song", []string{"Simon & Garfunkel"}, SearchOptions{}, "song simon and garfunkel"},
+		{"Euphoria", []string{"Loreen"}, SearchOptions{Region: "SE"}, "euphoria loreen|gl=SE"},
+		{"Euphoria", []string{"Loreen"}, SearchOptions{Region: "US", Language: "en"}, "euphoria loreen|gl=US|hl=en"},
+		{"Angels", []string{"The xx"}, SearchOptions{Album: "Coexist"}, "angels the xx|album=coexist"},
 	}
 	
 	for _, test := range tests {
-		result := ys.buildCacheKey(test.title, test.artists)
+		result := ys.buildCacheKey(test.title, test.artists, test.opts)
 		if result != test.expected {
-			t.Errorf("buildCacheKey(%q, %v) = %q; want %q", test.title, test.artists, result, test.expected)
+			t.Errorf("buildCacheKey(%q, %v, %+v) = %q; want %q", test.title, test.artists, test.opts, result, test.expected)
 		}
 	}
 }
@@ -208,4 +221,87 @@ func TestYouTubeService_NetworkError(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for network failure")
 	}
-}
\ No newline at end of file
+}
+func TestYouTubeService_OutboundHeaders(t *testing.T) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		w.Write([]byte(`{"videoId":"headers123"}`))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	config.UserAgents = []string{"agent-one", "agent-two"}
+	config.AcceptLanguage = "sv-SE"
+	config.Headers = map[string]string{"X-Custom": "yes"}
+	config.Language = "sv"
+	config.Region = "SE"
+
+	ys, err := NewYouTubeServiceWithConfig(config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, title := range []string{"One", "Two", "Three"} {
+		if _, err := ys.SearchVideos(title, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	expectedAgents := []string{"agent-one", "agent-two", "agent-one"}
+	for i, req := range requests {
+		if req.Header.Get("User-Agent") != expectedAgents[i] {
+			t.Errorf("Expected User-Agent %q on request %d, got %q", expectedAgents[i], i, req.Header.Get("User-Agent"))
+		}
+		if req.Header.Get("Accept-Language") != "sv-SE" {
+			t.Errorf("Expected Accept-Language sv-SE, got %q", req.Header.Get("Accept-Language"))
+		}
+		if req.Header.Get("X-Custom") != "yes" {
+			t.Errorf("Expected custom header, got %q", req.Header.Get("X-Custom"))
+		}
+		if req.URL.Query().Get("hl") != "sv" || req.URL.Query().Get("gl") != "SE" {
+			t.Errorf("Expected hl=sv and gl=SE, got %q", req.URL.RawQuery)
+		}
+	}
+}
+
+func TestYouTubeService_RegionAwareSearch(t *testing.T) {
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.Write([]byte(`{"videoId":"` + r.URL.Query().Get("gl") + `video"}`))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	config.Region = "US"
+
+	ys, err := NewYouTubeServiceWithConfig(config)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	swedish, err := ys.SearchVideosWithOptions("Euphoria", []string{"Loreen"}, SearchOptions{Region: "SE", Language: "sv"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	american, err := ys.SearchVideos("Euphoria", []string{"Loreen"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if swedish[0] != "SEvideo" || american[0] != "USvideo" {
+		t.Errorf("Expected region-specific results, got %v and %v", swedish, american)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("Expected markets to be cached separately, got %d requests", len(queries))
+	}
+	if queries[0].Get("hl") != "sv" || queries[0].Get("gl") != "SE" {
+		t.Errorf("Expected hl=sv&gl=SE, got %v", queries[0])
+	}
+	if queries[1].Get("gl") != "US" || queries[1].Get("hl") != "" {
+		t.Errorf("Expected configured region only, got %v", queries[1])
+	}
+}